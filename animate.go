@@ -0,0 +1,256 @@
+package yoga
+
+/*
+#include "cgo_wrapper.h"
+*/
+import "C"
+import "math"
+
+// LayoutCapture is the computed layout of a tree at one moment, with the
+// border box of every node keyed by node identity. Nodes destroyed after
+// the capture may be confused with nodes created later at the same address.
+type LayoutCapture struct {
+	nodes map[C.YGNodeRef]*capturedNode
+	// order 是先序遍历顺序
+	order []*capturedNode
+}
+
+type capturedNode struct {
+	node   *Node
+	id     string
+	path   string
+	parent *capturedNode
+	rect   Rect
+}
+
+// CaptureLayout records the border boxes of root and its descendants in
+// the coordinates of the root's parent. Nodes with display none are not
+// captured, so they fade in and out like added and removed nodes.
+func CaptureLayout(root *Node) *LayoutCapture {
+	c := &LayoutCapture{nodes: make(map[C.YGNodeRef]*capturedNode)}
+	captured := make(map[*layoutBox]*capturedNode)
+	for _, box := range layoutBoxes(root) {
+		n := &capturedNode{
+			node:   box.node,
+			id:     box.node.GetID(),
+			path:   box.path,
+			parent: captured[box.parent],
+			rect:   Rect{X: box.x, Y: box.y, Width: box.layout.Width, Height: box.layout.Height},
+		}
+		captured[box] = n
+		c.nodes[box.node.node] = n
+		c.order = append(c.order, n)
+	}
+	return c
+}
+
+// Rect returns the captured border box of n
+func (c *LayoutCapture) Rect(n *Node) (Rect, bool) {
+	if c == nil || n == nil {
+		return Rect{}, false
+	}
+	if captured, ok := c.nodes[n.node]; ok {
+		return captured.rect, true
+	}
+	return Rect{}, false
+}
+
+// AnimatedNode is the state of a node at one moment of a transition
+type AnimatedNode struct {
+	// Node is nil for disappearing nodes, which may already be destroyed,
+	// use ID and Path to identify them
+	Node *Node
+	ID   string
+	// Path is the index path in the tree the node is in at the end of the
+	// transition, or at the start for disappearing nodes
+	Path    string
+	Rect    Rect
+	Opacity float32
+	// Appearing and Disappearing are set for nodes only captured in the
+	// target or the source layout
+	Appearing, Disappearing bool
+}
+
+// Interpolate returns the rects of the nodes at progress t of the
+// transition from one captured layout to another, t going from 0 to 1.
+// Easing maps t to the progress of the motion, nil is linear.
+//
+// Nodes in both captures move and resize between their rects. Nodes only
+// in to appear and nodes only in from disappear: they keep their offset
+// and size relative to their parent, follow the parent as it moves and
+// fade in or out. The result lists the nodes of to in pre-order followed
+// by the disappearing nodes.
+func Interpolate(from, to *LayoutCapture, t float32, easing Easing) []AnimatedNode {
+	if easing == nil {
+		easing = Linear
+	}
+	t = min(max(t, 0), 1)
+	p := easing(t)
+	opacity := min(max(p, 0), 1)
+
+	var result []AnimatedNode
+	// 记录每个节点在当前时刻的 rect，子节点相对父节点定位
+	current := make(map[*capturedNode]Rect)
+	relative := func(n *capturedNode, parent *capturedNode) Rect {
+		if parent == nil {
+			return n.rect
+		}
+		r, ok := current[parent]
+		if !ok {
+			return n.rect
+		}
+		return Rect{
+			X:      r.X + n.rect.X - parent.rect.X,
+			Y:      r.Y + n.rect.Y - parent.rect.Y,
+			Width:  n.rect.Width,
+			Height: n.rect.Height,
+		}
+	}
+
+	if to != nil {
+		for _, n := range to.order {
+			item := AnimatedNode{Node: n.node, ID: n.id, Path: n.path}
+			if old, ok := from.lookup(n.node); ok {
+				item.Rect = lerpRect(old.rect, n.rect, p)
+				item.Opacity = 1
+			} else {
+				item.Rect = relative(n, n.parent)
+				item.Opacity = opacity
+				item.Appearing = true
+			}
+			current[n] = item.Rect
+			result = append(result, item)
+		}
+	}
+	if from != nil {
+		for _, n := range from.order {
+			if _, ok := to.lookup(n.node); ok {
+				continue
+			}
+			// 消失的节点跟随仍然存在的父节点
+			parent := n.parent
+			if parent != nil {
+				if moved, ok := to.lookup(parent.node); ok {
+					current[parent] = current[moved]
+				}
+			}
+			item := AnimatedNode{
+				ID:           n.id,
+				Path:         n.path,
+				Rect:         relative(n, parent),
+				Opacity:      1 - opacity,
+				Disappearing: true,
+			}
+			current[n] = item.Rect
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (c *LayoutCapture) lookup(n *Node) (*capturedNode, bool) {
+	if c == nil {
+		return nil, false
+	}
+	captured, ok := c.nodes[n.node]
+	return captured, ok
+}
+
+func lerpRect(a, b Rect, p float32) Rect {
+	return Rect{
+		X:      a.X + (b.X-a.X)*p,
+		Y:      a.Y + (b.Y-a.Y)*p,
+		Width:  max(a.Width+(b.Width-a.Width)*p, 0),
+		Height: max(a.Height+(b.Height-a.Height)*p, 0),
+	}
+}
+
+// Easing maps the progress of a transition from 0 to 1 to the progress of
+// the motion, which starts at 0 and ends at 1 but may overshoot in between
+type Easing func(t float32) float32
+
+// Linear moves at constant speed
+func Linear(t float32) float32 {
+	return t
+}
+
+// the CSS timing functions
+var (
+	Ease      = CubicBezier(0.25, 0.1, 0.25, 1)
+	EaseIn    = CubicBezier(0.42, 0, 1, 1)
+	EaseOut   = CubicBezier(0, 0, 0.58, 1)
+	EaseInOut = CubicBezier(0.42, 0, 0.58, 1)
+)
+
+// CubicBezier returns the easing of a CSS cubic-bezier() timing function
+// with the control points x1, y1 and x2, y2; x1 and x2 are clamped to 0..1
+func CubicBezier(x1, y1, x2, y2 float32) Easing {
+	x1, x2 = min(max(x1, 0), 1), min(max(x2, 0), 1)
+	bezier := func(s, p1, p2 float64) float64 {
+		// B(s) = 3(1-s)²s p1 + 3(1-s)s² p2 + s³
+		return 3*(1-s)*(1-s)*s*p1 + 3*(1-s)*s*s*p2 + s*s*s
+	}
+	return func(t float32) float32 {
+		if t <= 0 || t >= 1 {
+			return min(max(t, 0), 1)
+		}
+		x := float64(t)
+		// 二分求解 B_x(s) = t，B_x 在 x1、x2 属于 0..1 时单调
+		lo, hi := 0.0, 1.0
+		s := x
+		for i := 0; i < 50; i++ {
+			bx := bezier(s, float64(x1), float64(x2))
+			if math.Abs(bx-x) < 1e-7 {
+				break
+			}
+			if bx < x {
+				lo = s
+			} else {
+				hi = s
+			}
+			s = (lo + hi) / 2
+		}
+		return float32(bezier(s, float64(y1), float64(y2)))
+	}
+}
+
+// Spring returns the easing of a damped spring with unit mass pulling from
+// 0 to 1, starting at rest. The transition lasts until the spring settles
+// within 0.1% of the target; low damping overshoots and oscillates. The
+// stiffness and damping must be positive.
+func Spring(stiffness, damping float32) Easing {
+	k := math.Max(float64(stiffness), 1e-6)
+	c := math.Max(float64(damping), 1e-6)
+	omega := math.Sqrt(k)
+	zeta := c / (2 * omega)
+
+	var position func(tau float64) float64
+	var decay float64
+	switch {
+	case zeta < 1:
+		wd := omega * math.Sqrt(1-zeta*zeta)
+		decay = zeta * omega
+		position = func(tau float64) float64 {
+			return 1 - math.Exp(-decay*tau)*(math.Cos(wd*tau)+decay/wd*math.Sin(wd*tau))
+		}
+	case zeta == 1:
+		decay = omega
+		position = func(tau float64) float64 {
+			return 1 - math.Exp(-omega*tau)*(1+omega*tau)
+		}
+	default:
+		root := math.Sqrt(zeta*zeta - 1)
+		r1, r2 := -omega*(zeta-root), -omega*(zeta+root)
+		decay = -r1
+		position = func(tau float64) float64 {
+			return 1 - (r2*math.Exp(r1*tau)-r1*math.Exp(r2*tau))/(r2-r1)
+		}
+	}
+	duration := math.Log(1000) / decay
+	return func(t float32) float32 {
+		if t <= 0 || t >= 1 {
+			return min(max(t, 0), 1)
+		}
+		return float32(position(float64(t) * duration))
+	}
+}
@@ -0,0 +1,92 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestEasing(t *testing.T) {
+	r := assert.New(t)
+	near := func(want, got float32) {
+		t.Helper()
+		r.True(got > want-0.001 && got < want+0.001, want, got)
+	}
+	near(0.5, CubicBezier(0, 0, 1, 1)(0.5))
+	near(0.5, EaseInOut(0.5))
+	r.True(EaseIn(0.25) < 0.25)
+	r.True(EaseOut(0.25) > 0.25)
+	r.Equal(float32(1), Ease(1))
+	r.Equal(float32(0), Ease(-1))
+
+	bouncy := Spring(170, 8)
+	overshoot := false
+	for i := 1; i < 100; i++ {
+		if bouncy(float32(i)/100) > 1 {
+			overshoot = true
+		}
+	}
+	r.True(overshoot)
+	r.Equal(float32(1), bouncy(1))
+	stiff := Spring(100, 40)
+	for i := 1; i < 100; i++ {
+		p := stiff(float32(i) / 100)
+		r.True(p >= 0 && p <= 1)
+	}
+	r.True(stiff(0.999) > 0.99)
+}
+
+func TestInterpolate(t *testing.T) {
+	r := assert.New(t)
+	root, nodes := Build(nil, Row(Width(100), Height(50),
+		Box(ID("a"), Width(20)),
+		Box(ID("b"), Width(30), Box(ID("b0"), Margin(5), Width(10), Height(10))),
+	))
+	defer root.FreeRecursive()
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	before := CaptureLayout(root)
+	rect, ok := before.Rect(nodes["b"])
+	r.True(ok)
+	r.Equal(Rect{X: 20, Y: 0, Width: 30, Height: 50}, rect)
+
+	// a 变宽，b0 被移除，b 新增子节点 c
+	nodes["a"].SetWidth(60)
+	nodes["b"].RemoveChild(nodes["b0"])
+	defer nodes["b0"].Destroy()
+	c := newChildNode(root.GetConfig())
+	c.SetID("c")
+	c.SetWidth(10)
+	c.SetHeight(10)
+	c.SetPosition(EdgeTop, 20)
+	nodes["b"].InsertChild(c, 0)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	after := CaptureLayout(root)
+
+	frame := func(p float32) map[string]AnimatedNode {
+		m := make(map[string]AnimatedNode)
+		for _, n := range Interpolate(before, after, p, nil) {
+			m[n.ID] = n
+		}
+		return m
+	}
+	start, mid, end := frame(0), frame(0.5), frame(1)
+	r.Equal(Rect{X: 20, Y: 0, Width: 30, Height: 50}, start["b"].Rect)
+	r.Equal(Rect{X: 40, Y: 0, Width: 30, Height: 50}, mid["b"].Rect)
+	r.Equal(Rect{X: 60, Y: 0, Width: 30, Height: 50}, end["b"].Rect)
+	r.Equal(Rect{X: 0, Y: 0, Width: 40, Height: 50}, mid["a"].Rect)
+
+	r.True(mid["c"].Appearing)
+	r.Equal(float32(0.5), mid["c"].Opacity)
+	r.Equal(Rect{X: 40, Y: 20, Width: 10, Height: 10}, mid["c"].Rect)
+	r.Equal("1/0", mid["c"].Path)
+
+	r.True(mid["b0"].Disappearing)
+	r.True(mid["b0"].Node == nil)
+	r.NotNil(mid["c"].Node)
+	r.Equal(float32(1), start["b0"].Opacity)
+	r.Equal(float32(0), end["b0"].Opacity)
+	r.Equal(Rect{X: 45, Y: 5, Width: 10, Height: 10}, mid["b0"].Rect)
+	r.Equal(float32(1), mid["b"].Opacity)
+
+	r.Equal(4, len(Interpolate(nil, after, 0.5, EaseOut)))
+}
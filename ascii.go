@@ -0,0 +1,121 @@
+package yoga
+
+import (
+	"math"
+	"strings"
+)
+
+// box drawing connections of a grid cell
+const (
+	asciiUp uint8 = 1 << iota
+	asciiRight
+	asciiDown
+	asciiLeft
+)
+
+var asciiLines = [16]rune{
+	' ', '│', '─', '└', '│', '│', '┌', '├',
+	'─', '┘', '─', '┴', '┐', '┤', '┬', '┼',
+}
+
+// asciiOverflow marks the top right corner of nodes that had overflow
+const asciiOverflow = '!'
+
+// RenderASCII draws the border boxes of root and its descendants on a grid
+// of cols x rows characters, scaling the layout to fill the grid. Boxes are
+// drawn with box-drawing characters joined where they meet, labeled with
+// their id (or their context when that is a string or a fmt.Stringer) on
+// their top edge, and nodes where HadOverflow is true get a '!' in their
+// top right corner. Trailing spaces are trimmed from every line.
+//
+//	┌root─────────!
+//	│┌a────┐┌b────┤
+//	││     ││     │
+//	│└─────┘└─────┤
+//	└─────────────┘
+func RenderASCII(root *Node, cols, rows int) string {
+	boxes := layoutBoxes(root)
+	if len(boxes) == 0 || cols <= 0 || rows <= 0 {
+		return ""
+	}
+	minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for _, box := range boxes {
+		minX = min(minX, box.x)
+		minY = min(minY, box.y)
+		maxX = max(maxX, box.x+box.layout.Width)
+		maxY = max(maxY, box.y+box.layout.Height)
+	}
+	scaleX, scaleY := float32(0), float32(0)
+	if maxX > minX {
+		scaleX = float32(cols-1) / (maxX - minX)
+	}
+	if maxY > minY {
+		scaleY = float32(rows-1) / (maxY - minY)
+	}
+	toCol := func(x float32) int { return int(math.Round(float64((x - minX) * scaleX))) }
+	toRow := func(y float32) int { return int(math.Round(float64((y - minY) * scaleY))) }
+
+	lines := make([][]uint8, rows)
+	text := make([][]rune, rows)
+	for i := range lines {
+		lines[i] = make([]uint8, cols)
+		text[i] = make([]rune, cols)
+	}
+	connect := func(row, col int, mask uint8) {
+		lines[row][col] |= mask
+	}
+
+	for _, box := range boxes {
+		if box.layout.Width <= 0 || box.layout.Height <= 0 {
+			continue
+		}
+		x0, x1 := toCol(box.x), toCol(box.x+box.layout.Width)
+		y0, y1 := toRow(box.y), toRow(box.y+box.layout.Height)
+		for x := x0; x <= x1; x++ {
+			var mask uint8
+			if x > x0 {
+				mask |= asciiLeft
+			}
+			if x < x1 {
+				mask |= asciiRight
+			}
+			connect(y0, x, mask)
+			connect(y1, x, mask)
+		}
+		for y := y0; y <= y1; y++ {
+			var mask uint8
+			if y > y0 {
+				mask |= asciiUp
+			}
+			if y < y1 {
+				mask |= asciiDown
+			}
+			connect(y, x0, mask)
+			connect(y, x1, mask)
+		}
+
+		// 标签写在上边框上，放不下时截断
+		if label := []rune(defaultLabel(box.node)); len(label) > 0 && x1-x0 > 1 {
+			label = label[:min(len(label), x1-x0-1)]
+			copy(text[y0][x0+1:], label)
+		}
+		if box.node.HadOverflow() {
+			text[y0][x1] = asciiOverflow
+		}
+	}
+
+	var str strings.Builder
+	for y := range lines {
+		row := make([]rune, cols)
+		for x, mask := range lines[y] {
+			row[x] = asciiLines[mask]
+			if text[y][x] != 0 {
+				row[x] = text[y][x]
+			}
+		}
+		str.WriteString(strings.TrimRight(string(row), " "))
+		str.WriteByte('\n')
+	}
+	return str.String()
+}
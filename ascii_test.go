@@ -0,0 +1,35 @@
+package yoga
+
+import "testing"
+
+func TestRenderASCII(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	defer root.FreeRecursive()
+	root.SetID("root")
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(140)
+	root.SetHeight(40)
+	root.SetPadding(EdgeAll, 10)
+	root.SetGap(GutterColumn, 10)
+	for _, id := range []string{"a", "b"} {
+		child := newChildNode(config)
+		child.SetID(id)
+		child.SetWidth(60)
+		root.InsertChild(child, root.GetChildCount())
+	}
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	expected := "" +
+		"┌root─────────!\n" +
+		"│┌a────┐┌b────┤\n" +
+		"││     ││     │\n" +
+		"│└─────┘└─────┤\n" +
+		"└─────────────┘\n"
+	if got := RenderASCII(root, 15, 5); got != expected {
+		t.Errorf("unexpected rendering:\n%s\nexpected:\n%s", got, expected)
+	}
+	if got := RenderASCII(root, 0, 5); got != "" {
+		t.Errorf("expected empty rendering for an empty grid, got %q", got)
+	}
+}
@@ -0,0 +1,367 @@
+package yoga
+
+// Option configures a node created by Build. Nested Box, Row and Column
+// specs are options too and add a child to the node.
+//
+// Options are named after the property they set; when that name is taken
+// by the type of the property, such as FlexDirection or Display, the option
+// has an Option suffix.
+type Option interface {
+	apply(b *builder, n *Node)
+}
+
+type option func(n *Node)
+
+func (o option) apply(b *builder, n *Node) {
+	o(n)
+}
+
+// Spec describes a node and its children for Build
+type Spec struct {
+	opts []Option
+}
+
+func (s *Spec) apply(b *builder, parent *Node) {
+	child := newChildNode(b.config)
+	parent.InsertChild(child, parent.GetChildCount())
+	b.configure(child, s)
+}
+
+// Box describes a node with the given options and children
+func Box(opts ...Option) *Spec {
+	return &Spec{opts: opts}
+}
+
+// Row describes a node laying out its children in a row
+func Row(opts ...Option) *Spec {
+	return &Spec{opts: append([]Option{FlexDirectionOption(FlexDirectionRow)}, opts...)}
+}
+
+// Column describes a node laying out its children in a column
+func Column(opts ...Option) *Spec {
+	return &Spec{opts: append([]Option{FlexDirectionOption(FlexDirectionColumn)}, opts...)}
+}
+
+type builder struct {
+	config *Config
+	named  map[string]*Node
+}
+
+func (b *builder) configure(n *Node, s *Spec) {
+	for _, opt := range s.opts {
+		if opt != nil {
+			opt.apply(b, n)
+		}
+	}
+	if id := n.GetID(); id != "" {
+		b.named[id] = n
+	}
+}
+
+// Build creates the tree described by root, e.g.
+//
+//	root, nodes := yoga.Build(config, yoga.Row(yoga.Gap(8), yoga.Padding(16),
+//		yoga.Box(yoga.ID("title"), yoga.Grow(1), yoga.Measure(measure)),
+//		yoga.Box(yoga.Width(40)),
+//	))
+//
+// It returns the root and the nodes with an id, keyed by id. The root owns
+// its descendants; release the whole tree with FreeRecursive. A nil config
+// uses the default config.
+func Build(config *Config, root *Spec) (*Node, map[string]*Node) {
+	if config == nil {
+		config = NewConfig()
+	}
+	b := &builder{config: config, named: make(map[string]*Node)}
+	n := NewNodeWithConfig(config)
+	if root != nil {
+		b.configure(n, root)
+	}
+	return n, b.named
+}
+
+// ID sets the id of the node, the node is returned by Build under this id
+func ID(id string) Option { return option(func(n *Node) { n.SetID(id) }) }
+
+// Classes sets the class names of the node
+func Classes(classes ...string) Option {
+	return option(func(n *Node) { n.SetClasses(classes...) })
+}
+
+// Context sets the context of the node
+func Context(context any) Option { return option(func(n *Node) { n.SetContext(context) }) }
+
+// Measure sets the measure func of the node
+func Measure(fn MeasureFunc) Option { return option(func(n *Node) { n.SetMeasureFunc(fn) }) }
+
+// Dirtied sets the dirtied func of the node
+func Dirtied(fn DirtiedFunc) Option { return option(func(n *Node) { n.SetDirtiedFunc(fn) }) }
+
+// NodeTypeOption sets the node type
+func NodeTypeOption(t NodeType) Option { return option(func(n *Node) { n.SetNodeType(t) }) }
+
+// ReferenceBaseline makes the node the reference baseline of its parent
+func ReferenceBaseline() Option {
+	return option(func(n *Node) { n.SetIsReferenceBaseline(true) })
+}
+
+// FormsContainingBlock makes the node a containing block for absolute descendants
+func FormsContainingBlock() Option {
+	return option(func(n *Node) { n.SetAlwaysFormsContainingBlock(true) })
+}
+
+// AvoidBreakInside asks Paginate not to break the node across pages
+func AvoidBreakInside() Option {
+	return option(func(n *Node) { n.SetAvoidBreakInside(true) })
+}
+
+// KeepWithNext asks Paginate not to break between the node and its next sibling
+func KeepWithNext() Option {
+	return option(func(n *Node) { n.SetKeepWithNext(true) })
+}
+
+// RepeatHeader repeats the node on every page its parent continues on
+func RepeatHeader() Option {
+	return option(func(n *Node) { n.SetRepeatHeader(true) })
+}
+
+// DirectionOption sets the direction
+func DirectionOption(d Direction) Option { return option(func(n *Node) { n.SetDirection(d) }) }
+
+// FlexDirectionOption sets the flex direction
+func FlexDirectionOption(d FlexDirection) Option {
+	return option(func(n *Node) { n.SetFlexDirection(d) })
+}
+
+// JustifyContent sets justify-content
+func JustifyContent(j Justify) Option { return option(func(n *Node) { n.SetJustifyContent(j) }) }
+
+// AlignContent sets align-content
+func AlignContent(a Align) Option { return option(func(n *Node) { n.SetAlignContent(a) }) }
+
+// AlignItems sets align-items
+func AlignItems(a Align) Option { return option(func(n *Node) { n.SetAlignItems(a) }) }
+
+// AlignSelf sets align-self
+func AlignSelf(a Align) Option { return option(func(n *Node) { n.SetAlignSelf(a) }) }
+
+// FlexWrap sets flex-wrap
+func FlexWrap(w Wrap) Option { return option(func(n *Node) { n.SetFlexWrap(w) }) }
+
+// PositionTypeOption sets the position type
+func PositionTypeOption(p PositionType) Option {
+	return option(func(n *Node) { n.SetPositionType(p) })
+}
+
+// Absolute positions the node absolutely
+func Absolute() Option { return PositionTypeOption(PositionTypeAbsolute) }
+
+// OverflowOption sets overflow
+func OverflowOption(o Overflow) Option { return option(func(n *Node) { n.SetOverflow(o) }) }
+
+// DisplayOption sets display
+func DisplayOption(d Display) Option { return option(func(n *Node) { n.SetDisplay(d) }) }
+
+// BoxSizingOption sets box-sizing
+func BoxSizingOption(b BoxSizing) Option { return option(func(n *Node) { n.SetBoxSizing(b) }) }
+
+// Flex sets the flex shorthand
+func Flex(flex float32) Option { return option(func(n *Node) { n.SetFlex(flex) }) }
+
+// Grow sets flex-grow
+func Grow(grow float32) Option { return option(func(n *Node) { n.SetFlexGrow(grow) }) }
+
+// Shrink sets flex-shrink
+func Shrink(shrink float32) Option { return option(func(n *Node) { n.SetFlexShrink(shrink) }) }
+
+// Basis sets flex-basis in points
+func Basis(basis float32) Option { return option(func(n *Node) { n.SetFlexBasis(basis) }) }
+
+// BasisPercent sets flex-basis in percent
+func BasisPercent(basis float32) Option {
+	return option(func(n *Node) { n.SetFlexBasisPercent(basis) })
+}
+
+// BasisAuto sets flex-basis to auto
+func BasisAuto() Option { return option(func(n *Node) { n.SetFlexBasisAuto() }) }
+
+// BasisMaxContent sets flex-basis to max-content
+func BasisMaxContent() Option { return option(func(n *Node) { n.SetFlexBasisMaxContent() }) }
+
+// BasisFitContent sets flex-basis to fit-content
+func BasisFitContent() Option { return option(func(n *Node) { n.SetFlexBasisFitContent() }) }
+
+// BasisStretch sets flex-basis to stretch
+func BasisStretch() Option { return option(func(n *Node) { n.SetFlexBasisStretch() }) }
+
+// Width sets the width in points
+func Width(width float32) Option { return option(func(n *Node) { n.SetWidth(width) }) }
+
+// WidthPercent sets the width in percent
+func WidthPercent(width float32) Option { return option(func(n *Node) { n.SetWidthPercent(width) }) }
+
+// WidthAuto sets the width to auto
+func WidthAuto() Option { return option(func(n *Node) { n.SetWidthAuto() }) }
+
+// WidthMaxContent sets the width to max-content
+func WidthMaxContent() Option { return option(func(n *Node) { n.SetWidthMaxContent() }) }
+
+// WidthFitContent sets the width to fit-content
+func WidthFitContent() Option { return option(func(n *Node) { n.SetWidthFitContent() }) }
+
+// WidthStretch sets the width to stretch
+func WidthStretch() Option { return option(func(n *Node) { n.SetWidthStretch() }) }
+
+// Height sets the height in points
+func Height(height float32) Option { return option(func(n *Node) { n.SetHeight(height) }) }
+
+// HeightPercent sets the height in percent
+func HeightPercent(height float32) Option {
+	return option(func(n *Node) { n.SetHeightPercent(height) })
+}
+
+// HeightAuto sets the height to auto
+func HeightAuto() Option { return option(func(n *Node) { n.SetHeightAuto() }) }
+
+// HeightMaxContent sets the height to max-content
+func HeightMaxContent() Option { return option(func(n *Node) { n.SetHeightMaxContent() }) }
+
+// HeightFitContent sets the height to fit-content
+func HeightFitContent() Option { return option(func(n *Node) { n.SetHeightFitContent() }) }
+
+// HeightStretch sets the height to stretch
+func HeightStretch() Option { return option(func(n *Node) { n.SetHeightStretch() }) }
+
+// MinWidth sets min-width in points
+func MinWidth(minWidth float32) Option { return option(func(n *Node) { n.SetMinWidth(minWidth) }) }
+
+// MinWidthPercent sets min-width in percent
+func MinWidthPercent(minWidth float32) Option {
+	return option(func(n *Node) { n.SetMinWidthPercent(minWidth) })
+}
+
+// MinWidthMaxContent sets min-width to max-content
+func MinWidthMaxContent() Option { return option(func(n *Node) { n.SetMinWidthMaxContent() }) }
+
+// MinWidthFitContent sets min-width to fit-content
+func MinWidthFitContent() Option { return option(func(n *Node) { n.SetMinWidthFitContent() }) }
+
+// MinWidthStretch sets min-width to stretch
+func MinWidthStretch() Option { return option(func(n *Node) { n.SetMinWidthStretch() }) }
+
+// MinHeight sets min-height in points
+func MinHeight(minHeight float32) Option { return option(func(n *Node) { n.SetMinHeight(minHeight) }) }
+
+// MinHeightPercent sets min-height in percent
+func MinHeightPercent(minHeight float32) Option {
+	return option(func(n *Node) { n.SetMinHeightPercent(minHeight) })
+}
+
+// MinHeightMaxContent sets min-height to max-content
+func MinHeightMaxContent() Option { return option(func(n *Node) { n.SetMinHeightMaxContent() }) }
+
+// MinHeightFitContent sets min-height to fit-content
+func MinHeightFitContent() Option { return option(func(n *Node) { n.SetMinHeightFitContent() }) }
+
+// MinHeightStretch sets min-height to stretch
+func MinHeightStretch() Option { return option(func(n *Node) { n.SetMinHeightStretch() }) }
+
+// MaxWidth sets max-width in points
+func MaxWidth(maxWidth float32) Option { return option(func(n *Node) { n.SetMaxWidth(maxWidth) }) }
+
+// MaxWidthPercent sets max-width in percent
+func MaxWidthPercent(maxWidth float32) Option {
+	return option(func(n *Node) { n.SetMaxWidthPercent(maxWidth) })
+}
+
+// MaxWidthMaxContent sets max-width to max-content
+func MaxWidthMaxContent() Option { return option(func(n *Node) { n.SetMaxWidthMaxContent() }) }
+
+// MaxWidthFitContent sets max-width to fit-content
+func MaxWidthFitContent() Option { return option(func(n *Node) { n.SetMaxWidthFitContent() }) }
+
+// MaxWidthStretch sets max-width to stretch
+func MaxWidthStretch() Option { return option(func(n *Node) { n.SetMaxWidthStretch() }) }
+
+// MaxHeight sets max-height in points
+func MaxHeight(maxHeight float32) Option { return option(func(n *Node) { n.SetMaxHeight(maxHeight) }) }
+
+// MaxHeightPercent sets max-height in percent
+func MaxHeightPercent(maxHeight float32) Option {
+	return option(func(n *Node) { n.SetMaxHeightPercent(maxHeight) })
+}
+
+// MaxHeightMaxContent sets max-height to max-content
+func MaxHeightMaxContent() Option { return option(func(n *Node) { n.SetMaxHeightMaxContent() }) }
+
+// MaxHeightFitContent sets max-height to fit-content
+func MaxHeightFitContent() Option { return option(func(n *Node) { n.SetMaxHeightFitContent() }) }
+
+// MaxHeightStretch sets max-height to stretch
+func MaxHeightStretch() Option { return option(func(n *Node) { n.SetMaxHeightStretch() }) }
+
+// AspectRatio sets the aspect ratio
+func AspectRatio(ratio float32) Option { return option(func(n *Node) { n.SetAspectRatio(ratio) }) }
+
+// Margin sets the margin of all edges in points
+func Margin(margin float32) Option { return MarginEdge(EdgeAll, margin) }
+
+// MarginEdge sets the margin of an edge in points
+func MarginEdge(edge Edge, margin float32) Option {
+	return option(func(n *Node) { n.SetMargin(edge, margin) })
+}
+
+// MarginPercent sets the margin of an edge in percent
+func MarginPercent(edge Edge, margin float32) Option {
+	return option(func(n *Node) { n.SetMarginPercent(edge, margin) })
+}
+
+// MarginAuto sets the margin of an edge to auto
+func MarginAuto(edge Edge) Option { return option(func(n *Node) { n.SetMarginAuto(edge) }) }
+
+// Padding sets the padding of all edges in points
+func Padding(padding float32) Option { return PaddingEdge(EdgeAll, padding) }
+
+// PaddingEdge sets the padding of an edge in points
+func PaddingEdge(edge Edge, padding float32) Option {
+	return option(func(n *Node) { n.SetPadding(edge, padding) })
+}
+
+// PaddingPercent sets the padding of an edge in percent
+func PaddingPercent(edge Edge, padding float32) Option {
+	return option(func(n *Node) { n.SetPaddingPercent(edge, padding) })
+}
+
+// Border sets the border width of all edges
+func Border(border float32) Option { return BorderEdge(EdgeAll, border) }
+
+// BorderEdge sets the border width of an edge
+func BorderEdge(edge Edge, border float32) Option {
+	return option(func(n *Node) { n.SetBorder(edge, border) })
+}
+
+// Position sets the position of an edge in points
+func Position(edge Edge, position float32) Option {
+	return option(func(n *Node) { n.SetPosition(edge, position) })
+}
+
+// PositionPercent sets the position of an edge in percent
+func PositionPercent(edge Edge, position float32) Option {
+	return option(func(n *Node) { n.SetPositionPercent(edge, position) })
+}
+
+// PositionAuto sets the position of an edge to auto
+func PositionAuto(edge Edge) Option { return option(func(n *Node) { n.SetPositionAuto(edge) }) }
+
+// Gap sets the gap between rows and columns in points
+func Gap(gap float32) Option { return GapGutter(GutterAll, gap) }
+
+// GapGutter sets the gap of a gutter in points
+func GapGutter(gutter Gutter, gap float32) Option {
+	return option(func(n *Node) { n.SetGap(gutter, gap) })
+}
+
+// GapPercent sets the gap of a gutter in percent
+func GapPercent(gutter Gutter, gap float32) Option {
+	return option(func(n *Node) { n.SetGapPercent(gutter, gap) })
+}
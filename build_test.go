@@ -0,0 +1,36 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestBuild(t *testing.T) {
+	r := assert.New(t)
+	config := NewConfig()
+	root, nodes := Build(config, Row(Gap(8), Padding(16), Width(200), AlignItems(AlignFlexStart),
+		Box(ID("icon"), Width(20), Height(20)),
+		Column(ID("body"), Grow(1), Shrink(1),
+			Box(ID("title"), Context("Title"), Measure(func(width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+				return Size{Width: width, Height: 12}
+			})),
+			Box(Height(30), MarginEdge(EdgeTop, 4)),
+		),
+		Box(ID("hidden"), DisplayOption(DisplayNone)),
+	))
+	defer root.FreeRecursive()
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	r.Equal(4, len(nodes))
+	r.Equal(uint32(3), root.GetChildCount())
+	r.Equal(FlexDirectionRow, root.GetFlexDirection())
+	r.Equal(FlexDirectionColumn, nodes["body"].GetFlexDirection())
+	r.Equal(uint32(2), nodes["body"].GetChildCount())
+	r.Equal(Layout{Left: 16, Top: 16, Width: 20, Height: 20}, nodes["icon"].GetComputedLayout())
+	r.Equal(Layout{Left: 44, Top: 16, Width: 140, Height: 46}, nodes["body"].GetComputedLayout())
+	r.Equal(Layout{Left: 0, Top: 0, Width: 140, Height: 12}, nodes["title"].GetComputedLayout())
+	r.Equal(float32(78), root.GetComputedHeight())
+	r.Equal(nodes["title"].node, nodes["body"].GetChild(0).node)
+	r.Equal("Title", root.GetChild(1).GetChild(0).GetContext())
+}
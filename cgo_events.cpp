@@ -0,0 +1,57 @@
+#include <atomic>
+#include <mutex>
+
+#include <yoga/event/event.h>
+#include <yoga/node/Node.h>
+
+#include "cgo_wrapper.h"
+
+using namespace facebook::yoga;
+
+// 同一时间只追踪一个节点，其它节点的事件直接忽略，避免回调 Go 的开销
+static std::atomic<YGNodeConstRef> tracedNode{nullptr};
+static std::once_flag subscribeOnce;
+
+static void onEvent(YGNodeConstRef node, Event::Type type, Event::Data data) {
+    if (node == nullptr || node != tracedNode.load(std::memory_order_acquire)) {
+        return;
+    }
+    YGTraceEvent event = {};
+    event.type = type;
+    switch (type) {
+    case Event::NodeLayout:
+        event.layoutType = static_cast<int>(data.get<Event::NodeLayout>().layoutType);
+        break;
+    case Event::MeasureCallbackEnd: {
+        const auto& measure = data.get<Event::MeasureCallbackEnd>();
+        event.width = measure.width;
+        event.widthMode = measure.widthMeasureMode;
+        event.height = measure.height;
+        event.heightMode = measure.heightMeasureMode;
+        event.measuredWidth = measure.measuredWidth;
+        event.measuredHeight = measure.measuredHeight;
+        event.reason = static_cast<int>(measure.reason);
+        break;
+    }
+    default:
+        return;
+    }
+    goTraceEvent(node, &event);
+}
+
+void yg_trace_node(YGNodeConstRef node) {
+    std::call_once(subscribeOnce, [] { Event::subscribe(onEvent); });
+    tracedNode.store(node, std::memory_order_release);
+}
+
+float yg_node_computed_flex_basis(YGNodeConstRef node) {
+    return static_cast<const Node*>(node)->getLayout().computedFlexBasis.unwrap();
+}
+
+void yg_node_mark_dirty_and_propagate(YGNodeRef node) {
+    static_cast<Node*>(node)->markDirtyAndPropagate();
+}
+
+const char* yg_layout_pass_reason(int reason) {
+    return LayoutPassReasonToString(static_cast<LayoutPassReason>(reason));
+}
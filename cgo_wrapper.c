@@ -7,6 +7,7 @@
 // Forward declaration of Go functions
 extern int goBridgeLogger(YGConfigConstRef config, YGNodeConstRef node, YGLogLevel level, char* message);
 extern YGSize goMeasureInvoke(YGNodeRef node, float width, YGMeasureMode widthMode, float height, YGMeasureMode heightMode);
+extern float goBaselineInvoke(YGNodeConstRef node, float width, float height);
 
 // C bridge function that calls our Go logger
 int c_bridge_yg_logger(YGConfigConstRef config, YGNodeConstRef node, YGLogLevel level, const char* format, va_list args) {
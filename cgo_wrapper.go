@@ -4,20 +4,49 @@ package yoga
 #include <stdint.h>
 #include <stdarg.h>
 #include <stdio.h>
+#include <stdlib.h>
 #include <string.h>
 #include <yoga/Yoga.h>
 
 */
 import "C"
 import (
+	"reflect"
 	"runtime/cgo"
+	"slices"
 	"unsafe"
 )
 
 // NodeContext 存储节点的回调句柄，避免全局 map 查找
+//
+// NodeContext 由 C 堆分配并挂在 YGNode 的 context 上，只能包含句柄这类
+// 不含 Go 指针的字段，Go 侧数据通过句柄间接引用。
 type NodeContext struct {
-	measureHandle cgo.Handle
-	// 可以扩展其他节点级回调：dirtiedHandle, baselineHandle 等
+	measureHandle  cgo.Handle
+	attrsHandle    cgo.Handle
+	baselineHandle cgo.Handle
+	// 可以扩展其他节点级回调：dirtiedHandle 等
+}
+
+// nodeAttrs holds Go-side attributes of a node that must survive the
+// wrapper-per-call design of GetChild/GetParent.
+type nodeAttrs struct {
+	// context 是 SetContext 设置的值
+	context any
+	id      string
+	classes []string
+	// config keeps the config of the node alive, see NewNodeWithConfig
+	config *Config
+	// 滚动偏移，用于命中测试和绝对坐标
+	scrollX, scrollY float32
+	// 分页提示，见 paginate.go
+	breakHints breakHint
+	// 响应式样式变体，baseStyle 保存应用变体之前的样式
+	variants       []styleVariant
+	activeVariants []int
+	baseStyle      *Node
+	// SetData 的类型化数据槽，按类型区分
+	data map[reflect.Type]*dataSlot
 }
 
 func wrapConfigRef(ref C.YGConfigConstRef) *Config {
@@ -83,6 +112,26 @@ func goMeasureInvoke(node C.YGNodeRef, width C.float, widthMode C.YGMeasureMode,
 	return out
 }
 
+//export goBaselineInvoke
+func goBaselineInvoke(node C.YGNodeConstRef, width C.float, height C.float) C.float {
+	contextPtr := C.YGNodeGetContext(node)
+	if contextPtr == nil {
+		return 0
+	}
+	h := (*NodeContext)(contextPtr).baselineHandle
+	if h == 0 {
+		return 0
+	}
+	switch bf := h.Value().(type) {
+	case BaselineFunc:
+		return C.float(bf(float32(width), float32(height)))
+	case NodeBaselineFunc:
+		// 传入不持有节点的包装
+		return C.float(bf(wrapNodeRef(node), float32(width), float32(height)))
+	}
+	return 0
+}
+
 // 获取或创建节点的 NodeContext
 func getNodeContext(node C.YGNodeRef) *NodeContext {
 	if node == nil {
@@ -94,8 +143,8 @@ func getNodeContext(node C.YGNodeRef) *NodeContext {
 		return (*NodeContext)(contextPtr)
 	}
 
-	// 创建新的 NodeContext
-	ctx := &NodeContext{}
+	// 创建新的 NodeContext，分配在 C 堆上以免被 GC 回收
+	ctx := (*NodeContext)(C.calloc(1, C.size_t(unsafe.Sizeof(NodeContext{}))))
 	C.YGNodeSetContext(node, unsafe.Pointer(ctx))
 	return ctx
 }
@@ -114,11 +163,93 @@ func deleteNodeContext(node C.YGNodeRef) {
 		if ctx.measureHandle != 0 {
 			ctx.measureHandle.Delete()
 		}
+		// 清理 attrs handle 和其中的基础样式节点
+		if ctx.attrsHandle != 0 {
+			if attrs := ctx.attrsHandle.Value().(*nodeAttrs); attrs.baseStyle != nil {
+				attrs.baseStyle.Destroy()
+			}
+			ctx.attrsHandle.Delete()
+		}
+		// 清理 baseline handle
+		if ctx.baselineHandle != 0 {
+			ctx.baselineHandle.Delete()
+		}
+		C.free(contextPtr)
 	}
 
 	C.YGNodeSetContext(node, nil)
 }
 
+// 递归清理子树的 NodeContext，只处理由该节点拥有的子节点，与 YGNodeFreeRecursive 一致
+func deleteNodeContextRecursive(node C.YGNodeRef) {
+	count := C.YGNodeGetChildCount(node)
+	for i := C.size_t(0); i < count; i++ {
+		child := C.YGNodeGetChild(node, i)
+		if C.YGNodeGetOwner(child) == node {
+			deleteNodeContextRecursive(child)
+		}
+	}
+	deleteNodeContext(node)
+}
+
+// 为克隆节点复制一份独立的 NodeContext
+//
+// YGNodeClone 会原样复制 context 指针，两个节点共享同一个 NodeContext 会导致
+// 释放时重复删除句柄，因此克隆后需要重新创建句柄。
+func cloneNodeContext(src, dst C.YGNodeRef) {
+	C.YGNodeSetContext(dst, nil)
+	srcPtr := C.YGNodeGetContext(src)
+	if srcPtr == nil {
+		return
+	}
+	srcCtx := (*NodeContext)(srcPtr)
+	dstCtx := getNodeContext(dst)
+	if srcCtx.measureHandle != 0 {
+		dstCtx.measureHandle = cgo.NewHandle(srcCtx.measureHandle.Value())
+	}
+	if srcCtx.baselineHandle != 0 {
+		dstCtx.baselineHandle = cgo.NewHandle(srcCtx.baselineHandle.Value())
+	}
+	if srcCtx.attrsHandle != 0 {
+		attrs := *srcCtx.attrsHandle.Value().(*nodeAttrs)
+		attrs.classes = append([]string(nil), attrs.classes...)
+		attrs.variants = slices.Clone(attrs.variants)
+		attrs.activeVariants = slices.Clone(attrs.activeVariants)
+		attrs.data = cloneData(attrs.data)
+		if attrs.baseStyle != nil {
+			// 基础样式节点随 nodeAttrs 释放，克隆需要自己的副本
+			base := newChildNode(attrs.baseStyle.GetConfig())
+			base.CopyStyle(attrs.baseStyle)
+			attrs.baseStyle = base
+		}
+		dstCtx.attrsHandle = cgo.NewHandle(&attrs)
+	}
+}
+
+// 获取节点的 nodeAttrs，create 为 true 时按需创建
+func getNodeAttrs(node C.YGNodeRef, create bool) *nodeAttrs {
+	if node == nil {
+		return nil
+	}
+	if !create {
+		contextPtr := C.YGNodeGetContext(node)
+		if contextPtr == nil {
+			return nil
+		}
+		ctx := (*NodeContext)(contextPtr)
+		if ctx.attrsHandle == 0 {
+			return nil
+		}
+		return ctx.attrsHandle.Value().(*nodeAttrs)
+	}
+
+	ctx := getNodeContext(node)
+	if ctx.attrsHandle == 0 {
+		ctx.attrsHandle = cgo.NewHandle(&nodeAttrs{})
+	}
+	return ctx.attrsHandle.Value().(*nodeAttrs)
+}
+
 // 设置节点的 MeasureFunc
 func setMeasureHandle(node C.YGNodeRef, measureFunc MeasureFunc) {
 	ctx := getNodeContext(node)
@@ -154,6 +285,24 @@ func deleteMeasureHandle(node C.YGNodeRef) {
 	}
 }
 
+// 设置节点的 BaselineFunc 或 NodeBaselineFunc，nil 表示删除
+func setBaselineHandle(node C.YGNodeRef, baselineFunc any) {
+	if baselineFunc == nil && C.YGNodeGetContext(node) == nil {
+		return
+	}
+	ctx := getNodeContext(node)
+	if ctx == nil {
+		return
+	}
+	if ctx.baselineHandle != 0 {
+		ctx.baselineHandle.Delete()
+		ctx.baselineHandle = 0
+	}
+	if baselineFunc != nil {
+		ctx.baselineHandle = cgo.NewHandle(baselineFunc)
+	}
+}
+
 // 获取节点的 MeasureHandle（用于 goMeasureInvoke）
 func getMeasureHandleByNode(node C.YGNodeRef) cgo.Handle {
 	if node == nil {
@@ -7,9 +7,39 @@
 
 // Go functions that are called from C
 extern YGSize goMeasureInvoke(YGNodeRef node, float width, YGMeasureMode widthMode, float height, YGMeasureMode heightMode);
+extern float goBaselineInvoke(YGNodeConstRef node, float width, float height);
 extern int goBridgeLogger(YGConfigConstRef config, YGNodeConstRef node, YGLogLevel level, char* message);
 
 // C functions that are called from Go
 extern int c_bridge_yg_logger(YGConfigConstRef config, YGNodeConstRef node, YGLogLevel level, const char* format, va_list args);
 
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// YGTraceEvent carries the data of a Yoga event to Go, see cgo_events.cpp
+typedef struct {
+    int type;
+    int layoutType;
+    float width;
+    int widthMode;
+    float height;
+    int heightMode;
+    float measuredWidth;
+    float measuredHeight;
+    int reason;
+} YGTraceEvent;
+
+extern void goTraceEvent(YGNodeConstRef node, YGTraceEvent* event);
+
+// Event tracing and layout internals, implemented in cgo_events.cpp
+extern void yg_trace_node(YGNodeConstRef node);
+extern float yg_node_computed_flex_basis(YGNodeConstRef node);
+extern void yg_node_mark_dirty_and_propagate(YGNodeRef node);
+extern const char* yg_layout_pass_reason(int reason);
+
+#ifdef __cplusplus
+}
+#endif
+
 #endif // CGO_WRAPPER_H
\ No newline at end of file
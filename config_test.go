@@ -1,7 +1,9 @@
 package yoga
 
 import (
+	"runtime"
 	"testing"
+	"weak"
 
 	"github.com/dnsoa/go/assert"
 )
@@ -146,3 +148,19 @@ func TestConfigContextWithNodeLogger(t *testing.T) {
 		t.Error("Expected nil logger func after unset")
 	}
 }
+
+func TestNodeKeepsConfig(t *testing.T) {
+	r := assert.New(t)
+	config := weak.Make(NewConfig())
+	config.Value().SetPointScaleFactor(1)
+	node := NewNodeWithConfig(config.Value())
+	defer node.Destroy()
+
+	// 只有节点引用 config 时，config 也不能被回收
+	runtime.GC()
+	runtime.GC()
+	r.True(config.Value() != nil)
+	node.SetWidthPercent(33.3)
+	node.CalculateLayout(100, 100, DirectionLTR)
+	r.Equal(float32(33), node.GetComputedWidth())
+}
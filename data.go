@@ -0,0 +1,108 @@
+package yoga
+
+import "reflect"
+
+// dataSlot is a value stored with SetData and the hook copying it to clones
+type dataSlot struct {
+	value any
+	// set 为 false 时槽中只有 clone hook
+	set   bool
+	clone func(any) any
+}
+
+func dataKey[T any]() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// SetData stores v in the slot of type T of the node. Every type has its
+// own slot, so a node can carry several values of distinct types next to
+// the single context of SetContext.
+func SetData[T any](n *Node, v T) {
+	if n == nil || n.node == nil {
+		return
+	}
+	attrs := getNodeAttrs(n.node, true)
+	if attrs.data == nil {
+		attrs.data = make(map[reflect.Type]*dataSlot)
+	}
+	key := dataKey[T]()
+	if slot, ok := attrs.data[key]; ok {
+		slot.value, slot.set = v, true
+		return
+	}
+	attrs.data[key] = &dataSlot{value: v, set: true}
+}
+
+// Data gets the value in the slot of type T of the node
+func Data[T any](n *Node) (T, bool) {
+	var zero T
+	if n == nil {
+		return zero, false
+	}
+	attrs := getNodeAttrs(n.node, false)
+	if attrs == nil {
+		return zero, false
+	}
+	slot, ok := attrs.data[dataKey[T]()]
+	if !ok || !slot.set {
+		return zero, false
+	}
+	// 接口类型的 nil 值不能直接断言
+	v, _ := slot.value.(T)
+	return v, true
+}
+
+// DeleteData empties the slot of type T of the node, its clone hook is kept
+func DeleteData[T any](n *Node) {
+	if n == nil {
+		return
+	}
+	if attrs := getNodeAttrs(n.node, false); attrs != nil {
+		if slot, ok := attrs.data[dataKey[T]()]; ok {
+			slot.value, slot.set = nil, false
+		}
+	}
+}
+
+// SetDataClone sets the hook Clone uses to copy the value in the slot of
+// type T, e.g. to deep-copy a slice or a struct with pointers. Without a
+// hook clones share the value. The hook is kept when the value changes and
+// passed on to clones.
+func SetDataClone[T any](n *Node, clone func(v T) T) {
+	if n == nil || n.node == nil {
+		return
+	}
+	attrs := getNodeAttrs(n.node, true)
+	if attrs.data == nil {
+		attrs.data = make(map[reflect.Type]*dataSlot)
+	}
+	key := dataKey[T]()
+	slot, ok := attrs.data[key]
+	if !ok {
+		slot = &dataSlot{}
+		attrs.data[key] = slot
+	}
+	slot.clone = nil
+	if clone != nil {
+		slot.clone = func(v any) any {
+			t, _ := v.(T)
+			return clone(t)
+		}
+	}
+}
+
+// cloneData copies the data slots for a cloned node
+func cloneData(data map[reflect.Type]*dataSlot) map[reflect.Type]*dataSlot {
+	if data == nil {
+		return nil
+	}
+	cloned := make(map[reflect.Type]*dataSlot, len(data))
+	for key, slot := range data {
+		c := *slot
+		if c.set && c.clone != nil {
+			c.value = c.clone(c.value)
+		}
+		cloned[key] = &c
+	}
+	return cloned
+}
@@ -0,0 +1,67 @@
+package yoga
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+type testLabel string
+
+type testRows struct {
+	rows []int
+}
+
+func TestNodeData(t *testing.T) {
+	r := assert.New(t)
+	root, nodes := Build(nil, Column(Box(ID("item"))))
+	defer root.FreeRecursive()
+	item := nodes["item"]
+
+	_, ok := Data[testLabel](item)
+	r.False(ok)
+	SetData(item, testLabel("hello"))
+	SetData(item, &testRows{rows: []int{1, 2}})
+	SetData[error](item, nil)
+
+	// 通过 GetChild 返回的新包装也能取到数据
+	child := root.GetChild(0)
+	label, ok := Data[testLabel](child)
+	r.True(ok)
+	r.Equal(testLabel("hello"), label)
+	rows, ok := Data[*testRows](child)
+	r.True(ok)
+	r.Equal([]int{1, 2}, rows.rows)
+	err, ok := Data[error](child)
+	r.True(ok)
+	r.Equal(nil, err)
+	_, ok = Data[string](child)
+	r.False(ok)
+
+	SetDataClone(item, func(v *testRows) *testRows {
+		return &testRows{rows: slices.Clone(v.rows)}
+	})
+	clone := item.Clone()
+	defer clone.Destroy()
+	cloned, _ := Data[*testRows](clone)
+	cloned.rows[0] = 10
+	r.Equal(1, rows.rows[0])
+	clonedLabel, _ := Data[testLabel](clone)
+	r.Equal(testLabel("hello"), clonedLabel)
+
+	SetData(clone, testLabel("changed"))
+	label, _ = Data[testLabel](item)
+	r.Equal(testLabel("hello"), label)
+
+	DeleteData[*testRows](item)
+	_, ok = Data[*testRows](item)
+	r.False(ok)
+	SetData(item, &testRows{rows: []int{3}})
+	again := item.Clone()
+	defer again.Destroy()
+	copied, _ := Data[*testRows](again)
+	copied.rows[0] = 30
+	got, _ := Data[*testRows](item)
+	r.Equal(3, got.rows[0])
+}
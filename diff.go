@@ -0,0 +1,258 @@
+package yoga
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EdgeValues holds computed left, top, right and bottom values
+type EdgeValues struct {
+	Left   float32 `json:"left"`
+	Top    float32 `json:"top"`
+	Right  float32 `json:"right"`
+	Bottom float32 `json:"bottom"`
+}
+
+func computedEdges(get func(Edge) float32) EdgeValues {
+	return EdgeValues{Left: get(EdgeLeft), Top: get(EdgeTop), Right: get(EdgeRight), Bottom: get(EdgeBottom)}
+}
+
+// NodeLayout is the computed layout of a node in a LayoutSnapshot
+type NodeLayout struct {
+	ID          string        `json:"id,omitempty"`
+	Layout      Layout        `json:"layout"`
+	Margin      EdgeValues    `json:"margin"`
+	Border      EdgeValues    `json:"border"`
+	Padding     EdgeValues    `json:"padding"`
+	HadOverflow bool          `json:"hadOverflow,omitempty"`
+	Children    []*NodeLayout `json:"children,omitempty"`
+}
+
+// LayoutSnapshot records the computed layout of a tree, so it can be
+// compared with DiffLayoutSnapshots after the tree changed. Snapshots can
+// be stored as JSON to compare layouts across Yoga versions.
+type LayoutSnapshot struct {
+	Root *NodeLayout `json:"root"`
+}
+
+// NewLayoutSnapshot records the computed layout of root and its descendants
+func NewLayoutSnapshot(root *Node) *LayoutSnapshot {
+	if root == nil || root.node == nil {
+		return &LayoutSnapshot{}
+	}
+	var capture func(n *Node) *NodeLayout
+	capture = func(n *Node) *NodeLayout {
+		l := &NodeLayout{
+			ID:          n.GetID(),
+			Layout:      n.GetComputedLayout(),
+			Margin:      computedEdges(n.GetComputedMargin),
+			Border:      computedEdges(n.GetComputedBorder),
+			Padding:     computedEdges(n.GetComputedPadding),
+			HadOverflow: n.HadOverflow(),
+		}
+		childCount := n.GetChildCount()
+		for i := uint32(0); i < childCount; i++ {
+			l.Children = append(l.Children, capture(n.GetChild(i)))
+		}
+		return l
+	}
+	return &LayoutSnapshot{Root: capture(root)}
+}
+
+// LayoutChangeKind classifies a LayoutChange
+type LayoutChangeKind int
+
+const (
+	// LayoutChangeValue is a changed layout value, see LayoutChange.Property
+	LayoutChangeValue LayoutChangeKind = iota
+	// LayoutChangeOverflow is a change of HadOverflow, Before and After are 0 or 1
+	LayoutChangeOverflow
+	// LayoutChangeAdded is a node only present in the second tree
+	LayoutChangeAdded
+	// LayoutChangeRemoved is a node only present in the first tree
+	LayoutChangeRemoved
+	// LayoutChangeMoved is a node matched by id at another index,
+	// Before and After are its indexes
+	LayoutChangeMoved
+)
+
+// LayoutChange is a difference between two layouts
+type LayoutChange struct {
+	Kind LayoutChangeKind
+	// Path is the index path of the node such as "0/2", or "root". Removed
+	// nodes have their path in the first tree, other changes the path in
+	// the second tree.
+	Path string
+	ID   string
+	// Property is the changed value, one of left, top, width, height and
+	// margin-, border- or padding- followed by an edge, e.g. margin-left
+	Property string
+	Before   float32
+	After    float32
+}
+
+// String describes the change, e.g. "0/1 #sidebar: width 100 -> 120"
+func (c LayoutChange) String() string {
+	node := c.Path
+	if c.ID != "" {
+		node += " #" + c.ID
+	}
+	switch c.Kind {
+	case LayoutChangeOverflow:
+		return fmt.Sprintf("%s: overflow %t -> %t", node, c.Before != 0, c.After != 0)
+	case LayoutChangeAdded:
+		return node + ": added"
+	case LayoutChangeRemoved:
+		return node + ": removed"
+	case LayoutChangeMoved:
+		return fmt.Sprintf("%s: moved from index %s to %s", node, formatFloat(c.Before), formatFloat(c.After))
+	}
+	return fmt.Sprintf("%s: %s %s -> %s", node, c.Property, formatFloat(c.Before), formatFloat(c.After))
+}
+
+// FormatLayoutChanges returns a report with one change per line
+func FormatLayoutChanges(changes []LayoutChange) string {
+	var str strings.Builder
+	for _, c := range changes {
+		str.WriteString(c.String())
+		str.WriteByte('\n')
+	}
+	return str.String()
+}
+
+// DiffLayouts compares the computed layouts of two trees, such as the same
+// tree laid out with different errata. See DiffLayoutSnapshots.
+func DiffLayouts(a, b *Node, tolerance float32) []LayoutChange {
+	return DiffLayoutSnapshots(NewLayoutSnapshot(a), NewLayoutSnapshot(b), tolerance)
+}
+
+// DiffLayoutSnapshots compares two layout snapshots and returns the changes
+// in pre-order. Values differing by at most tolerance are equal.
+//
+// The roots are always matched. Children of matched nodes are matched by
+// id when they have one, otherwise by index; children matched by id at
+// another index are reported as moved.
+func DiffLayoutSnapshots(a, b *LayoutSnapshot, tolerance float32) []LayoutChange {
+	d := &layoutDiff{tolerance: tolerance}
+	switch {
+	case a.Root == nil && b.Root == nil:
+	case a.Root == nil:
+		d.structural(LayoutChangeAdded, b.Root, "root", 0, 0)
+	case b.Root == nil:
+		d.structural(LayoutChangeRemoved, a.Root, "root", 0, 0)
+	default:
+		d.node(a.Root, b.Root, "root")
+	}
+	return d.changes
+}
+
+type layoutDiff struct {
+	tolerance float32
+	changes   []LayoutChange
+}
+
+func (d *layoutDiff) structural(kind LayoutChangeKind, l *NodeLayout, path string, before, after int) {
+	d.changes = append(d.changes, LayoutChange{
+		Kind:   kind,
+		Path:   path,
+		ID:     l.ID,
+		Before: float32(before),
+		After:  float32(after),
+	})
+}
+
+func (d *layoutDiff) value(b *NodeLayout, path, property string, before, after float32) {
+	if IsNaN(before) && IsNaN(after) {
+		return
+	}
+	diff := before - after
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > d.tolerance || IsNaN(diff) {
+		d.changes = append(d.changes, LayoutChange{
+			Kind:     LayoutChangeValue,
+			Path:     path,
+			ID:       b.ID,
+			Property: property,
+			Before:   before,
+			After:    after,
+		})
+	}
+}
+
+func (d *layoutDiff) edges(b *NodeLayout, path, property string, before, after EdgeValues) {
+	d.value(b, path, property+"-left", before.Left, after.Left)
+	d.value(b, path, property+"-top", before.Top, after.Top)
+	d.value(b, path, property+"-right", before.Right, after.Right)
+	d.value(b, path, property+"-bottom", before.Bottom, after.Bottom)
+}
+
+func (d *layoutDiff) node(a, b *NodeLayout, path string) {
+	d.value(b, path, "left", a.Layout.Left, b.Layout.Left)
+	d.value(b, path, "top", a.Layout.Top, b.Layout.Top)
+	d.value(b, path, "width", a.Layout.Width, b.Layout.Width)
+	d.value(b, path, "height", a.Layout.Height, b.Layout.Height)
+	d.edges(b, path, "margin", a.Margin, b.Margin)
+	d.edges(b, path, "border", a.Border, b.Border)
+	d.edges(b, path, "padding", a.Padding, b.Padding)
+	if a.HadOverflow != b.HadOverflow {
+		c := LayoutChange{Kind: LayoutChangeOverflow, Path: path, ID: b.ID}
+		if a.HadOverflow {
+			c.Before = 1
+		} else {
+			c.After = 1
+		}
+		d.changes = append(d.changes, c)
+	}
+
+	childPath := func(i int) string {
+		if path == "root" {
+			return strconv.Itoa(i)
+		}
+		return path + "/" + strconv.Itoa(i)
+	}
+
+	// 先按 id 匹配，剩下没有 id 的按下标匹配
+	matched := make([]int, len(b.Children))
+	for i := range matched {
+		matched[i] = -1
+	}
+	used := make([]bool, len(a.Children))
+	byID := make(map[string]int)
+	for i, child := range a.Children {
+		if child.ID != "" {
+			byID[child.ID] = i
+		}
+	}
+	for j, child := range b.Children {
+		if i, ok := byID[child.ID]; ok && child.ID != "" && !used[i] {
+			matched[j] = i
+			used[i] = true
+		}
+	}
+	for j, child := range b.Children {
+		if matched[j] < 0 && child.ID == "" && j < len(a.Children) && !used[j] && a.Children[j].ID == "" {
+			matched[j] = j
+			used[j] = true
+		}
+	}
+
+	for i, child := range a.Children {
+		if !used[i] {
+			d.structural(LayoutChangeRemoved, child, childPath(i), i, 0)
+		}
+	}
+	for j, child := range b.Children {
+		i := matched[j]
+		if i < 0 {
+			d.structural(LayoutChangeAdded, child, childPath(j), 0, j)
+			continue
+		}
+		if i != j {
+			d.structural(LayoutChangeMoved, child, childPath(j), i, j)
+		}
+		d.node(a.Children[i], child, childPath(j))
+	}
+}
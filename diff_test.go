@@ -0,0 +1,90 @@
+package yoga
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffLayoutsSnapshots(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	defer root.FreeRecursive()
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(100)
+	root.SetHeight(20)
+	for _, id := range []string{"a", "b", ""} {
+		child := newChildNode(config)
+		child.SetID(id)
+		child.SetWidth(30)
+		root.InsertChild(child, root.GetChildCount())
+	}
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	before := NewLayoutSnapshot(root)
+
+	// swap a and b, pad the unnamed child and add one more
+	a := root.GetChild(0)
+	root.RemoveChild(a)
+	root.InsertChild(a, 1)
+	root.GetChild(2).SetPadding(EdgeLeft, 2)
+	extra := newChildNode(config)
+	extra.SetWidth(30)
+	root.InsertChild(extra, 3)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	// snapshots survive a JSON round trip
+	data, err := json.Marshal(NewLayoutSnapshot(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var after LayoutSnapshot
+	if err := json.Unmarshal(data, &after); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "" +
+		"root: overflow false -> true\n" +
+		"0 #b: moved from index 1 to 0\n" +
+		"0 #b: left 30 -> 0\n" +
+		"1 #a: moved from index 0 to 1\n" +
+		"1 #a: left 0 -> 30\n" +
+		"2: padding-left 0 -> 2\n" +
+		"3: added\n"
+	changes := DiffLayoutSnapshots(before, &after, 0.01)
+	if got := FormatLayoutChanges(changes); got != expected {
+		t.Errorf("unexpected changes:\n%s\nexpected:\n%s", got, expected)
+	}
+	if changes[2].Kind != LayoutChangeValue || changes[2].Property != "left" || changes[2].After != 0 {
+		t.Errorf("unexpected change %+v", changes[2])
+	}
+}
+
+func TestDiffLayoutsTrees(t *testing.T) {
+	build := func(errata Errata) *Node {
+		config := NewConfig()
+		config.SetErrata(errata)
+		root := NewNodeWithConfig(config)
+		root.SetWidth(50)
+		child := newChildNode(config)
+		child.SetHeight(10)
+		child.SetMargin(EdgeTop, 4)
+		root.InsertChild(child, 0)
+		root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+		return root
+	}
+	a, b := build(ErrataNone), build(ErrataClassic)
+	defer a.FreeRecursive()
+	defer b.FreeRecursive()
+	if changes := DiffLayouts(a, b, 0); len(changes) != 0 {
+		t.Errorf("expected no changes, got:\n%s", FormatLayoutChanges(changes))
+	}
+
+	b.GetChild(0).SetHeight(12)
+	b.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	changes := DiffLayouts(a, b, 1)
+	if got := FormatLayoutChanges(changes); got != "root: height 14 -> 16\n0: height 10 -> 12\n" {
+		t.Errorf("unexpected changes:\n%s", got)
+	}
+	if changes := DiffLayouts(a, b, 2); len(changes) != 0 {
+		t.Errorf("expected changes within tolerance to be ignored, got:\n%s", FormatLayoutChanges(changes))
+	}
+}
@@ -0,0 +1,477 @@
+package yoga
+
+/*
+#include "cgo_wrapper.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MeasureCall is a call of a measure func recorded by CalculateLayoutExplained
+type MeasureCall struct {
+	Width      float32
+	WidthMode  MeasureMode
+	Height     float32
+	HeightMode MeasureMode
+	Result     Size
+	// Reason is the layout pass that measured the node, e.g. "flex_measure"
+	Reason string
+}
+
+// LayoutExplanation is the trace of the steps that decided the size of a
+// node, see CalculateLayoutExplained
+type LayoutExplanation struct {
+	// MainAxis is the dimension along the parent's flex direction,
+	// DimensionWidth for a root
+	MainAxis Dimension
+	// FlexBasis is the flex basis computed by Yoga
+	FlexBasis float32
+	// MinMain and MaxMain are the resolved min and max main sizes, NaN when unset
+	MinMain float32
+	MaxMain float32
+	// HypotheticalMainSize is the flex basis clamped by min and max
+	HypotheticalMainSize float32
+	// FreeSpace is the free space of the node's flex line, negative when
+	// the items overflow it
+	FreeSpace float32
+	// Flexed is the space the node grew (positive) or shrank (negative) by
+	Flexed float32
+	// MainSize and CrossSize are the computed sizes
+	MainSize  float32
+	CrossSize float32
+	// Passes lists the layout passes of the node, e.g. "measure" or "cached layout"
+	Passes       []string
+	MeasureCalls []MeasureCall
+	// Steps is the readable trace, one step per line
+	Steps []string
+}
+
+// String returns the trace with one step per line
+func (e *LayoutExplanation) String() string {
+	return strings.Join(e.Steps, "\n") + "\n"
+}
+
+func (e *LayoutExplanation) step(format string, args ...any) {
+	e.Steps = append(e.Steps, fmt.Sprintf(format, args...))
+}
+
+// Yoga's facebook::yoga::Event::Type and LayoutType values
+const (
+	traceEventNodeLayout         = 2
+	traceEventMeasureCallbackEnd = 6
+)
+
+var layoutTypes = []string{"layout", "measure", "cached layout", "cached measure"}
+
+var (
+	// explainMu serializes explained layouts, Yoga can trace one node at a time
+	explainMu sync.Mutex
+	explained atomic.Pointer[LayoutExplanation]
+)
+
+//export goTraceEvent
+func goTraceEvent(node C.YGNodeConstRef, event *C.YGTraceEvent) {
+	e := explained.Load()
+	if e == nil {
+		return
+	}
+	switch int(event._type) {
+	case traceEventNodeLayout:
+		if t := int(event.layoutType); t >= 0 && t < len(layoutTypes) {
+			e.Passes = append(e.Passes, layoutTypes[t])
+		}
+	case traceEventMeasureCallbackEnd:
+		// measure 包装函数已经记录了调用，这里补上触发的布局原因
+		reason := C.GoString(C.yg_layout_pass_reason(event.reason))
+		if n := len(e.MeasureCalls); n > 0 && e.MeasureCalls[n-1].Reason == "" {
+			e.MeasureCalls[n-1].Reason = reason
+		}
+	}
+}
+
+// CalculateLayoutExplained calculates the layout of root like CalculateLayout
+// and explains how the size of target, a node of the tree, was decided: its
+// flex basis, hypothetical main size, the free space of its flex line and
+// how it was distributed, min and max clamping and the calls of its measure
+// func with their modes and results.
+//
+// The layout passes and measure reasons come from Yoga's event subsystem,
+// the measure calls from wrapping the measure func of target. target is
+// marked dirty first so it is laid out again even when the tree is clean.
+// The distribution of free space is reconstructed from the computed layout
+// and can differ from Yoga's result by the rounding to the pixel grid.
+func CalculateLayoutExplained(root, target *Node, ownerWidth, ownerHeight float32, direction Direction) *LayoutExplanation {
+	e := &LayoutExplanation{MinMain: Undefined, MaxMain: Undefined}
+	if root == nil || root.node == nil || target == nil || target.node == nil {
+		return e
+	}
+
+	explainMu.Lock()
+	defer explainMu.Unlock()
+
+	if measure := target.GetMeasureFunc(); measure != nil {
+		setMeasureHandle(target.node, func(width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+			size := measure(width, widthMode, height, heightMode)
+			e.MeasureCalls = append(e.MeasureCalls, MeasureCall{
+				Width: width, WidthMode: widthMode, Height: height, HeightMode: heightMode, Result: size,
+			})
+			return size
+		})
+		defer setMeasureHandle(target.node, measure)
+	}
+
+	explained.Store(e)
+	C.yg_trace_node(C.YGNodeConstRef(target.node))
+	C.yg_node_mark_dirty_and_propagate(target.node)
+	root.CalculateLayout(ownerWidth, ownerHeight, direction)
+	C.yg_trace_node(nil)
+	explained.Store(nil)
+
+	e.explain(target)
+	return e
+}
+
+// flexItem is an in-flow child of a flex container as seen by the explanation
+type flexItem struct {
+	node             *Node
+	basis            float32
+	hypothetical     float32
+	margins          float32
+	min, max         float32
+	paddingAndBorder float32
+	grow, shrink     float32
+}
+
+// bound clamps a main size by the min and max sizes and padding and border
+func (it *flexItem) bound(size float32) float32 {
+	if !IsNaN(it.max) && size > it.max {
+		size = it.max
+	}
+	if !IsNaN(it.min) && size < it.min {
+		size = it.min
+	}
+	return max(size, it.paddingAndBorder)
+}
+
+func (e *LayoutExplanation) explain(target *Node) {
+	parent := target.GetParent()
+	if parent == nil {
+		e.MainAxis = DimensionWidth
+		e.MainSize = target.GetComputedWidth()
+		e.CrossSize = target.GetComputedHeight()
+		e.step("root: width %s from %s, height %s from %s",
+			formatFloat(e.MainSize), sizeSource(target.GetWidth()),
+			formatFloat(e.CrossSize), sizeSource(target.GetHeight()))
+		e.explainMeasure()
+		e.explainPasses()
+		return
+	}
+
+	row := parent.GetFlexDirection() == FlexDirectionRow || parent.GetFlexDirection() == FlexDirectionRowReverse
+	e.MainAxis = DimensionHeight
+	if row {
+		e.MainAxis = DimensionWidth
+	}
+	innerMain := mainSize(parent, row) - mainEdges(parent.GetComputedPadding, row) - mainEdges(parent.GetComputedBorder, row)
+	e.step("parent: flex-direction %s, inner %s %s", parent.GetFlexDirection(), e.MainAxis, formatFloat(innerMain))
+
+	if target.GetPositionType() == PositionTypeAbsolute {
+		e.MainSize = mainSize(target, row)
+		e.CrossSize = mainSize(target, !row)
+		e.step("absolutely positioned: %s %s, not part of a flex line", e.MainAxis, formatFloat(e.MainSize))
+		e.explainMeasure()
+		e.explainPasses()
+		return
+	}
+
+	line, index := flexLine(parent, target, row, innerMain)
+	it := line[index]
+	e.FlexBasis = it.basis
+	e.MinMain, e.MaxMain = it.min, it.max
+	e.HypotheticalMainSize = it.hypothetical
+	e.step("flex basis: %s from %s", formatFloat(it.basis), basisSource(target, row))
+	if !IsNaN(it.min) || !IsNaN(it.max) {
+		e.step("min/max %s: %s / %s", e.MainAxis, formatBound(it.min), formatBound(it.max))
+	}
+	if it.hypothetical != it.basis {
+		e.step("hypothetical main size: %s (flex basis clamped)", formatFloat(it.hypothetical))
+	} else {
+		e.step("hypothetical main size: %s", formatFloat(it.hypothetical))
+	}
+
+	consumed := flexLineGaps(parent, row, innerMain, len(line))
+	var totalGrow, totalShrink float32
+	for _, item := range line {
+		consumed += item.hypothetical + item.margins
+		totalGrow += item.grow
+		totalShrink += item.shrink * item.basis
+	}
+	e.FreeSpace = innerMain - consumed
+	e.step("flex line: %d items, %s %s used of %s, free space %s",
+		len(line), formatFloat(consumed), e.MainAxis, formatFloat(innerMain), formatFloat(e.FreeSpace))
+
+	predicted := it.hypothetical
+	switch {
+	case e.FreeSpace > 0 && totalGrow > 0:
+		if totalGrow < 1 {
+			// Yoga 把小于 1 的 flex-grow 总和当作 1
+			totalGrow = 1
+		}
+		predicted = distribute(line, index, e.FreeSpace, totalGrow, func(item *flexItem) float32 { return item.grow })
+		e.step("grow: flex-grow %s of %s, %s + %s", formatFloat(it.grow), formatFloat(totalGrow),
+			formatFloat(it.hypothetical), formatFloat(predicted-it.hypothetical))
+	case e.FreeSpace < 0 && totalShrink > 0:
+		predicted = distribute(line, index, e.FreeSpace, totalShrink, func(item *flexItem) float32 { return item.shrink * item.basis })
+		e.step("shrink: flex-shrink %s x basis %s of %s, %s - %s", formatFloat(it.shrink), formatFloat(it.basis),
+			formatFloat(totalShrink), formatFloat(it.hypothetical), formatFloat(it.hypothetical-predicted))
+	case e.FreeSpace > 0:
+		e.step("no flex-grow in the line, free space is left to justify-content")
+	case e.FreeSpace < 0:
+		e.step("no flex-shrink in the line, the items overflow")
+	}
+	e.Flexed = predicted - it.hypothetical
+	e.MainSize = mainSize(target, row)
+	e.CrossSize = mainSize(target, !row)
+	e.step("main size: %s", formatFloat(e.MainSize))
+	if diff := e.MainSize - predicted; diff > 0.01 || diff < -0.01 {
+		e.step("  (%s before rounding to the pixel grid)", formatFloat(predicted))
+	}
+
+	align := target.GetAlignSelf()
+	if align == AlignAuto {
+		align = parent.GetAlignItems()
+	}
+	crossStyle := target.GetHeight()
+	if !row {
+		crossStyle = target.GetWidth()
+	}
+	crossDimension := DimensionWidth
+	if row {
+		crossDimension = DimensionHeight
+	}
+	if align == AlignStretch && (crossStyle.Unit == UnitUndefined || crossStyle.Unit == UnitAuto) {
+		e.step("cross size: %s %s, stretched to the line", crossDimension, formatFloat(e.CrossSize))
+	} else {
+		e.step("cross size: %s %s from %s (align %s)", crossDimension, formatFloat(e.CrossSize), sizeSource(crossStyle), align)
+	}
+	e.explainMeasure()
+	e.explainPasses()
+}
+
+func (e *LayoutExplanation) explainMeasure() {
+	for _, call := range e.MeasureCalls {
+		reason := ""
+		if call.Reason != "" {
+			reason = " [" + call.Reason + "]"
+		}
+		e.step("measure func(width %s %s, height %s %s) = %s x %s%s",
+			formatFloat(call.Width), call.WidthMode, formatFloat(call.Height), call.HeightMode,
+			formatFloat(call.Result.Width), formatFloat(call.Result.Height), reason)
+	}
+}
+
+func (e *LayoutExplanation) explainPasses() {
+	if len(e.Passes) > 0 {
+		e.step("layout passes: %s", strings.Join(e.Passes, ", "))
+	}
+}
+
+// distribute reproduces Yoga's two pass distribution of free space: items
+// whose share violates their min or max are frozen at the bound first, the
+// remaining space is then shared by the other items
+func distribute(line []*flexItem, index int, freeSpace, total float32, factor func(*flexItem) float32) float32 {
+	frozen := make([]bool, len(line))
+	remaining := freeSpace
+	for i, item := range line {
+		f := factor(item)
+		if f == 0 {
+			continue
+		}
+		candidate := item.hypothetical + freeSpace/total*f
+		if bounded := item.bound(candidate); bounded != candidate {
+			frozen[i] = true
+			remaining -= bounded - item.hypothetical
+			total -= f
+		}
+	}
+	it := line[index]
+	if frozen[index] || total <= 0 {
+		return it.bound(it.hypothetical + freeSpace/max(total, 1)*factor(it))
+	}
+	return it.bound(it.hypothetical + remaining/total*factor(it))
+}
+
+// flexLine returns the items of the flex line containing target and the
+// index of target in it, breaking lines like Yoga when the parent wraps
+func flexLine(parent, target *Node, row bool, innerMain float32) ([]*flexItem, int) {
+	gap := resolveGap(parent, row, innerMain)
+	wrap := parent.GetFlexWrap() != WrapNoWrap
+
+	var line []*flexItem
+	index := -1
+	var lineSize float32
+	childCount := parent.GetChildCount()
+	for i := uint32(0); i < childCount; i++ {
+		child := parent.GetChild(i)
+		if child.GetPositionType() == PositionTypeAbsolute || child.GetDisplay() == DisplayNone {
+			continue
+		}
+		it := newFlexItem(child, row, innerMain)
+		outer := it.hypothetical + it.margins
+		if len(line) > 0 {
+			outer += gap
+		}
+		if wrap && len(line) > 0 && lineSize+outer > innerMain {
+			if index >= 0 {
+				break
+			}
+			line, lineSize = nil, 0
+			outer -= gap
+		}
+		line = append(line, it)
+		lineSize += outer
+		if child.node == target.node {
+			index = len(line) - 1
+		}
+	}
+	return line, index
+}
+
+func newFlexItem(n *Node, row bool, innerMain float32) *flexItem {
+	it := &flexItem{
+		node:             n,
+		basis:            float32(C.yg_node_computed_flex_basis(C.YGNodeConstRef(n.node))),
+		paddingAndBorder: mainEdges(n.GetComputedPadding, row) + mainEdges(n.GetComputedBorder, row),
+		grow:             n.GetFlexGrow(),
+		shrink:           n.GetFlexShrink(),
+	}
+	if flex := n.GetFlex(); !IsNaN(flex) && flex != 0 {
+		// flex 简写：正数表示 grow，负数表示 shrink
+		if flex > 0 && it.grow == 0 {
+			it.grow = flex
+		} else if flex < 0 && it.shrink == 0 {
+			it.shrink = -flex
+		}
+	}
+	if IsNaN(it.grow) {
+		it.grow = 0
+	}
+	if IsNaN(it.shrink) {
+		it.shrink = 0
+	}
+	if row {
+		it.min, it.max = resolveBound(n.GetMinWidth(), innerMain), resolveBound(n.GetMaxWidth(), innerMain)
+	} else {
+		it.min, it.max = resolveBound(n.GetMinHeight(), innerMain), resolveBound(n.GetMaxHeight(), innerMain)
+	}
+	it.hypothetical = it.basis
+	if !IsNaN(it.max) && it.hypothetical > it.max {
+		it.hypothetical = it.max
+	}
+	if !IsNaN(it.min) && it.hypothetical < it.min {
+		it.hypothetical = it.min
+	}
+	if !hasAutoMainMargin(n, row) {
+		it.margins = mainEdges(n.GetComputedMargin, row)
+	}
+	return it
+}
+
+func mainSize(n *Node, row bool) float32 {
+	if row {
+		return n.GetComputedWidth()
+	}
+	return n.GetComputedHeight()
+}
+
+func mainEdges(get func(Edge) float32, row bool) float32 {
+	if row {
+		return get(EdgeLeft) + get(EdgeRight)
+	}
+	return get(EdgeTop) + get(EdgeBottom)
+}
+
+// hasAutoMainMargin reports whether n has an auto margin along the main
+// axis, auto margins take no space when the line is sized
+func hasAutoMainMargin(n *Node, row bool) bool {
+	edges := []Edge{EdgeTop, EdgeBottom, EdgeVertical, EdgeAll}
+	if row {
+		edges = []Edge{EdgeLeft, EdgeRight, EdgeStart, EdgeEnd, EdgeHorizontal, EdgeAll}
+	}
+	for _, edge := range edges {
+		if n.GetMargin(edge).Unit == UnitAuto {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveGap(parent *Node, row bool, innerMain float32) float32 {
+	gutter := GutterRow
+	if row {
+		gutter = GutterColumn
+	}
+	gap := parent.GetGap(gutter)
+	if gap.Unit == UnitUndefined {
+		gap = parent.GetGap(GutterAll)
+	}
+	if v := resolveBound(gap, innerMain); !IsNaN(v) {
+		return v
+	}
+	return 0
+}
+
+func flexLineGaps(parent *Node, row bool, innerMain float32, items int) float32 {
+	if items < 2 {
+		return 0
+	}
+	return resolveGap(parent, row, innerMain) * float32(items-1)
+}
+
+// resolveBound resolves a point or percent value against size, other
+// units resolve to NaN
+func resolveBound(v Value, size float32) float32 {
+	switch v.Unit {
+	case UnitPoint:
+		return v.Value
+	case UnitPercent:
+		return v.Value * size / 100
+	}
+	return Undefined
+}
+
+func formatBound(v float32) string {
+	if IsNaN(v) {
+		return "none"
+	}
+	return formatFloat(v)
+}
+
+func sizeSource(v Value) string {
+	switch v.Unit {
+	case UnitUndefined, UnitAuto:
+		return "content"
+	}
+	return "style " + v.String()
+}
+
+func basisSource(n *Node, row bool) string {
+	if basis := n.GetFlexBasis(); basis.Unit != UnitAuto && basis.Unit != UnitUndefined {
+		return "flex-basis " + basis.String()
+	}
+	size := n.GetHeight()
+	if row {
+		size = n.GetWidth()
+	}
+	if size.Unit == UnitPoint || size.Unit == UnitPercent {
+		return "style " + size.String()
+	}
+	if n.GetMeasureFunc() != nil {
+		return "measure func"
+	}
+	return "content"
+}
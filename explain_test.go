@@ -0,0 +1,61 @@
+package yoga
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestCalculateLayoutExplained(t *testing.T) {
+	r := assert.New(t)
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	defer root.FreeRecursive()
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(200)
+	root.SetHeight(50)
+	root.SetPadding(EdgeAll, 10)
+
+	fixed := newChildNode(config)
+	fixed.SetWidth(40)
+	root.InsertChild(fixed, 0)
+	grow := newChildNode(config)
+	grow.SetFlexGrow(1)
+	grow.SetMaxWidth(60)
+	root.InsertChild(grow, 1)
+	text := newChildNode(config)
+	text.SetFlexGrow(1)
+	text.SetMeasureFunc(func(width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+		return Size{Width: 20, Height: 10}
+	})
+	root.InsertChild(text, 2)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	e := CalculateLayoutExplained(root, grow, Undefined, Undefined, DirectionLTR)
+	r.Equal(DimensionWidth, e.MainAxis)
+	r.Equal(float32(0), e.FlexBasis)
+	r.Equal(float32(60), e.MaxMain)
+	r.Equal(float32(120), e.FreeSpace)
+	r.Equal(float32(60), e.Flexed)
+	r.Equal(float32(60), e.MainSize)
+	r.Equal(float32(30), e.CrossSize)
+	r.True(len(e.Passes) > 0)
+
+	// grow 被 max-width 冻结后，剩余空间全部给 text
+	e = CalculateLayoutExplained(root, text, Undefined, Undefined, DirectionLTR)
+	r.Equal(float32(20), e.FlexBasis)
+	r.Equal(float32(60), e.Flexed)
+	r.Equal(float32(80), e.MainSize)
+	// 最终布局的宽高都是 exactly，Yoga 不再调用 measure
+	r.Equal(1, len(e.MeasureCalls))
+	r.Equal(MeasureCall{
+		Width: 180, WidthMode: MeasureModeAtMost, Height: 30, HeightMode: MeasureModeExactly,
+		Result: Size{Width: 20, Height: 10}, Reason: "measure",
+	}, e.MeasureCalls[0])
+	r.True(strings.Contains(e.String(), "flex basis: 20 from measure func\n"))
+	r.NotNil(text.GetMeasureFunc())
+
+	e = CalculateLayoutExplained(root, root, Undefined, Undefined, DirectionLTR)
+	r.Equal("root: width 200 from style 200, height 50 from style 50\n", e.Steps[0]+"\n")
+}
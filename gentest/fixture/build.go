@@ -0,0 +1,223 @@
+package fixture
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/millken/yoga"
+)
+
+// Tree is a test case built into Yoga nodes
+type Tree struct {
+	Config *yoga.Config
+	Root   *yoga.Node
+	// Nodes holds every node in pre-order, in the same order as Walk visits
+	// the elements of the case
+	Nodes []*yoga.Node
+}
+
+// Free releases the nodes of the tree
+func (t *Tree) Free() {
+	for i := len(t.Nodes) - 1; i >= 0; i-- {
+		t.Nodes[i].Destroy()
+	}
+	t.Nodes = nil
+}
+
+// Walk calls visit for every element of the case in pre-order, with the
+// node name used by the generated tests such as root_child0_child1
+func (c *Case) Walk(visit func(e *Element, name string)) {
+	var walk func(e *Element, name string)
+	walk = func(e *Element, name string) {
+		visit(e, name)
+		for i, child := range e.Children {
+			walk(child, fmt.Sprintf("%s_child%d", name, i))
+		}
+	}
+	walk(c.Root, "root")
+}
+
+// rootStyle returns the styles of the root element, which the gentest
+// template positions absolutely unless the fixture says otherwise. The
+// implicit position goes after the alignment properties, where gentest
+// emits it.
+func rootStyle(e *Element) []yoga.Declaration {
+	at := len(e.Style)
+	for i, decl := range e.Style {
+		if decl.Property == "position" {
+			return e.Style
+		}
+		switch decl.Property {
+		case "direction", "flex-direction", "justify-content", "align-content", "align-items", "align-self":
+		default:
+			at = min(at, i)
+		}
+	}
+	style := append([]yoga.Declaration(nil), e.Style[:at]...)
+	style = append(style, yoga.Declaration{Property: "position", Value: "absolute"})
+	return append(style, e.Style[at:]...)
+}
+
+// Build creates the nodes of the case with a new config
+func (c *Case) Build() (*Tree, error) {
+	config := yoga.NewConfig()
+	for _, feature := range c.Experiments {
+		config.SetExperimentalFeatureEnabled(feature, true)
+	}
+	t := &Tree{Config: config}
+	var build func(e *Element, style []yoga.Declaration) (*yoga.Node, error)
+	build = func(e *Element, style []yoga.Declaration) (*yoga.Node, error) {
+		n := yoga.NewNodeWithConfig(config)
+		t.Nodes = append(t.Nodes, n)
+		n.SetID(e.ID)
+		for _, decl := range style {
+			if err := n.SetStyleProperty(decl.Property, decl.Value); err != nil {
+				return nil, err
+			}
+		}
+		if e.Text != "" {
+			n.SetMeasureFunc(IntrinsicSizeMeasureFunc(e.Text, n.GetFlexDirection()))
+		}
+		for i, child := range e.Children {
+			childNode, err := build(child, child.Style)
+			if err != nil {
+				return nil, err
+			}
+			n.InsertChild(childNode, uint32(i))
+		}
+		return n, nil
+	}
+	root, err := build(c.Root, rootStyle(c.Root))
+	if err != nil {
+		t.Free()
+		return nil, fmt.Errorf("case %s: %w", c.Name, err)
+	}
+	t.Root = root
+	return t, nil
+}
+
+// Verify builds the case, lays it out in both directions and compares the
+// result with the recorded layouts. Every mismatch is reported in the
+// returned error.
+func (c *Case) Verify() error {
+	t, err := c.Build()
+	if err != nil {
+		return err
+	}
+	defer t.Free()
+
+	var mismatches []string
+	for _, direction := range []yoga.Direction{yoga.DirectionLTR, yoga.DirectionRTL} {
+		t.Root.CalculateLayout(yoga.Undefined, yoga.Undefined, direction)
+		i := 0
+		c.Walk(func(e *Element, name string) {
+			n := t.Nodes[i]
+			i++
+			expected := e.LTR
+			if direction == yoga.DirectionRTL {
+				expected = e.RTL
+			}
+			actual := Layout{
+				Left:   n.GetComputedLeft(),
+				Top:    n.GetComputedTop(),
+				Width:  n.GetComputedWidth(),
+				Height: n.GetComputedHeight(),
+			}
+			if expected != actual {
+				mismatches = append(mismatches, fmt.Sprintf("%s %s: expected %v, got %v", direction, name, expected, actual))
+			}
+		})
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("case %s:\n  %s", c.Name, strings.Join(mismatches, "\n  "))
+	}
+	return nil
+}
+
+// String formats the layout as "left top width height", like the fixtures
+func (l Layout) String() string {
+	return fmt.Sprintf("%g %g %g %g", l.Left, l.Top, l.Width, l.Height)
+}
+
+// IntrinsicSizeMeasureFunc measures text the way the generated tests do,
+// every character is 10x10 and lines wrap between words.
+// This implements the same algorithm as the C++ version in Yoga's test utilities.
+func IntrinsicSizeMeasureFunc(text string, flexDirection yoga.FlexDirection) yoga.MeasureFunc {
+	return func(width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+		const widthPerChar float32 = 10.0
+		const heightPerChar float32 = 10.0
+		textWidth := float32(len(text)) * widthPerChar
+
+		var measuredWidth float32
+		switch widthMode {
+		case yoga.MeasureModeExactly:
+			measuredWidth = width
+		case yoga.MeasureModeAtMost:
+			measuredWidth = float32(math.Min(float64(textWidth), float64(width)))
+		default:
+			measuredWidth = textWidth
+		}
+
+		effectiveWidth := measuredWidth
+		if flexDirection != yoga.FlexDirectionColumn {
+			effectiveWidth = float32(math.Max(float64(longestWordWidth(text, widthPerChar)), float64(measuredWidth)))
+		}
+
+		var measuredHeight float32
+		switch heightMode {
+		case yoga.MeasureModeExactly:
+			measuredHeight = height
+		case yoga.MeasureModeAtMost:
+			measuredHeight = float32(math.Min(float64(textHeight(text, effectiveWidth, widthPerChar, heightPerChar)), float64(height)))
+		default:
+			measuredHeight = textHeight(text, effectiveWidth, widthPerChar, heightPerChar)
+		}
+		return yoga.Size{Width: measuredWidth, Height: measuredHeight}
+	}
+}
+
+func longestWordWidth(text string, widthPerChar float32) float32 {
+	longest := 0
+	for _, word := range strings.Split(text, " ") {
+		longest = max(longest, len(word))
+	}
+	return float32(longest) * widthPerChar
+}
+
+func textHeight(text string, measuredWidth, widthPerChar, heightPerChar float32) float32 {
+	if float32(len(text))*widthPerChar <= measuredWidth {
+		return heightPerChar
+	}
+
+	lines := 1
+	lineLength := float32(0)
+	for i, word := range strings.Split(text, " ") {
+		wordWidth := float32(len(word)) * widthPerChar
+		if wordWidth > measuredWidth {
+			// 单词比行宽还长，独占一行
+			if lineLength > 0 {
+				lines++
+			}
+			lines++
+			lineLength = 0
+			continue
+		}
+		spaceWidth := float32(0)
+		if lineLength > 0 {
+			spaceWidth = widthPerChar
+		}
+		if lineLength+spaceWidth+wordWidth <= measuredWidth {
+			lineLength += spaceWidth + wordWidth
+		} else {
+			if i > 0 {
+				lines++
+			}
+			lineLength = wordWidth
+		}
+	}
+	if lineLength == 0 {
+		lines--
+	}
+	return float32(lines) * heightPerChar
+}
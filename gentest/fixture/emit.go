@@ -0,0 +1,345 @@
+package fixture
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/millken/yoga"
+)
+
+// WriteTests writes the cases as a Go test file of package yoga_test, in
+// the format of the tests generated by gentest/gentest-driver.ts. source
+// names the fixture in the file header.
+func WriteTests(w io.Writer, source string, cases []*Case) error {
+	e := &emitter{check: yoga.NewNode()}
+	defer e.check.Destroy()
+	e.line("/*")
+	e.line(" * Copyright (c) Meta Platforms, Inc. and affiliates.")
+	e.line(" *")
+	e.line(" * This source code is licensed under the MIT license found in the")
+	e.line(" * LICENSE file in the root directory of this source tree.")
+	e.line(" *")
+	e.line(" * generated by gentest/fixturegen from %s", source)
+	e.line(" */")
+	e.line("")
+	e.line("package yoga_test")
+	e.line("")
+	e.line("import (")
+	e.line("\"github.com/dnsoa/go/assert\"")
+	e.line("\"github.com/millken/yoga\"")
+	e.line("\"testing\"")
+	e.line(")")
+	for _, c := range cases {
+		e.line("")
+		if err := e.testCase(c); err != nil {
+			return err
+		}
+	}
+
+	src, err := format.Source(e.buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated tests: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+type emitter struct {
+	buf bytes.Buffer
+	// check 用来检查样式声明
+	check *yoga.Node
+}
+
+func (e *emitter) line(format string, args ...any) {
+	fmt.Fprintf(&e.buf, format, args...)
+	e.buf.WriteByte('\n')
+}
+
+func (e *emitter) testCase(c *Case) error {
+	e.line("func Test%s(t *testing.T) {", exportName(c.Name))
+	if c.Disabled {
+		e.line("t.Skip()")
+		e.line("")
+	}
+	e.line("config := yoga.NewConfig()")
+	for _, feature := range c.Experiments {
+		e.line("config.SetExperimentalFeatureEnabled(yoga.ExperimentalFeature%s, true)", exportName(feature.String()))
+	}
+	e.line("")
+
+	var err error
+	c.Walk(func(el *Element, name string) {
+		if err != nil {
+			return
+		}
+		style := el.Style
+		if name == "root" {
+			style = rootStyle(el)
+		} else {
+			e.line("")
+		}
+		e.line("%s := yoga.NewNodeWithConfig(config)", name)
+		flexDirection := "yoga.FlexDirectionColumn"
+		for _, decl := range style {
+			var calls []string
+			if calls, err = e.setterCalls(decl); err != nil {
+				err = fmt.Errorf("case %s: %s: %w", c.Name, name, err)
+				return
+			}
+			for _, call := range calls {
+				e.line("%s.%s", name, call)
+			}
+			if decl.Property == "flex-direction" {
+				d, _ := yoga.FlexDirectionFromString(decl.Value)
+				flexDirection = goEnum(d)
+			}
+		}
+		if name != "root" {
+			parent := name[:strings.LastIndex(name, "_child")]
+			index := name[len(parent)+len("_child"):]
+			e.line("%s.InsertChild(%s, %s)", parent, name, index)
+		}
+		if el.Text != "" {
+			e.line("%s.SetMeasureFunc(intrinsicSizeMeasureFunc(%q, %s))", name, el.Text, flexDirection)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, direction := range []string{"LTR", "RTL"} {
+		if direction == "RTL" {
+			e.line("")
+		}
+		e.line("root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.Direction%s)", direction)
+		e.line("")
+		first := true
+		c.Walk(func(el *Element, name string) {
+			if !first {
+				e.line("")
+			}
+			first = false
+			layout := el.LTR
+			if direction == "RTL" {
+				layout = el.RTL
+			}
+			e.line("assert.Equal(t, %s, %s.GetComputedLeft())", formatNumber(layout.Left), name)
+			e.line("assert.Equal(t, %s, %s.GetComputedTop())", formatNumber(layout.Top), name)
+			e.line("assert.Equal(t, %s, %s.GetComputedWidth())", formatNumber(layout.Width), name)
+			e.line("assert.Equal(t, %s, %s.GetComputedHeight())", formatNumber(layout.Height), name)
+		})
+	}
+	e.line("config.Destroy()")
+	e.line("}")
+	return nil
+}
+
+// exportName converts snake_case and kebab-case names to CamelCase,
+// like toExportName in gentest-go.js
+func exportName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func formatNumber(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
+
+// enumProperties maps the style properties taking an enum value to their
+// Node setter and the parser of the value
+var enumProperties = map[string]struct {
+	method string
+	parse  func(string) (fmt.Stringer, error)
+}{
+	"direction":       {"SetDirection", enumParser(yoga.DirectionFromString)},
+	"flex-direction":  {"SetFlexDirection", enumParser(yoga.FlexDirectionFromString)},
+	"justify-content": {"SetJustifyContent", enumParser(yoga.JustifyFromString)},
+	"align-content":   {"SetAlignContent", enumParser(yoga.AlignFromString)},
+	"align-items":     {"SetAlignItems", enumParser(yoga.AlignFromString)},
+	"align-self":      {"SetAlignSelf", enumParser(yoga.AlignFromString)},
+	"position":        {"SetPositionType", enumParser(yoga.PositionTypeFromString)},
+	"flex-wrap":       {"SetFlexWrap", enumParser(wrapFromCSS)},
+	"overflow":        {"SetOverflow", enumParser(yoga.OverflowFromString)},
+	"display":         {"SetDisplay", enumParser(yoga.DisplayFromString)},
+	"box-sizing":      {"SetBoxSizing", enumParser(yoga.BoxSizingFromString)},
+}
+
+func enumParser[T fmt.Stringer](parse func(string) (T, error)) func(string) (fmt.Stringer, error) {
+	return func(s string) (fmt.Stringer, error) {
+		return parse(s)
+	}
+}
+
+func wrapFromCSS(s string) (yoga.Wrap, error) {
+	if s == "nowrap" {
+		return yoga.WrapNoWrap, nil
+	}
+	return yoga.WrapFromString(s)
+}
+
+var numberProperties = map[string]string{
+	"flex":         "SetFlex",
+	"flex-grow":    "SetFlexGrow",
+	"flex-shrink":  "SetFlexShrink",
+	"aspect-ratio": "SetAspectRatio",
+}
+
+// lengthProperty is the stem of the setters of a length property, e.g.
+// Margin for SetMarginPercent, and the Go expression of their edge or
+// gutter argument, if any
+type lengthProperty struct {
+	stem string
+	arg  string
+}
+
+var lengthProperties = map[string]lengthProperty{
+	"flex-basis":       {"FlexBasis", ""},
+	"width":            {"Width", ""},
+	"height":           {"Height", ""},
+	"min-width":        {"MinWidth", ""},
+	"min-height":       {"MinHeight", ""},
+	"max-width":        {"MaxWidth", ""},
+	"max-height":       {"MaxHeight", ""},
+	"gap":              {"Gap", "yoga.GutterAll"},
+	"row-gap":          {"Gap", "yoga.GutterRow"},
+	"column-gap":       {"Gap", "yoga.GutterColumn"},
+	"inset":            {"Position", "yoga.EdgeAll"},
+	"inset-horizontal": {"Position", "yoga.EdgeHorizontal"},
+	"inset-vertical":   {"Position", "yoga.EdgeVertical"},
+}
+
+// shorthandProperties maps the box shorthands to the stem of their setters
+var shorthandProperties = map[string]string{
+	"margin":       "Margin",
+	"padding":      "Padding",
+	"border":       "Border",
+	"border-width": "Border",
+}
+
+func init() {
+	for _, edge := range []string{"left", "top", "right", "bottom", "start", "end", "horizontal", "vertical"} {
+		arg := "yoga.Edge" + exportName(edge)
+		if edge != "horizontal" && edge != "vertical" {
+			lengthProperties[edge] = lengthProperty{"Position", arg}
+		}
+		lengthProperties["margin-"+edge] = lengthProperty{"Margin", arg}
+		lengthProperties["padding-"+edge] = lengthProperty{"Padding", arg}
+		lengthProperties["border-"+edge] = lengthProperty{"Border", arg}
+		lengthProperties["border-"+edge+"-width"] = lengthProperty{"Border", arg}
+	}
+}
+
+// setterCalls converts a declaration to the Node setter calls of the
+// generated tests, e.g. "margin: auto" to SetMarginAuto(yoga.EdgeAll)
+func (e *emitter) setterCalls(decl yoga.Declaration) ([]string, error) {
+	// 由 SetStyleProperty 检查声明，下面只需要把它转换成 setter 的名字
+	if err := e.check.SetStyleProperty(decl.Property, decl.Value); err != nil {
+		return nil, err
+	}
+	property, value := decl.Property, strings.TrimSpace(decl.Value)
+	if p, ok := enumProperties[property]; ok {
+		v, err := p.parse(value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("%s(%s)", p.method, goEnum(v))}, nil
+	}
+	if method, ok := numberProperties[property]; ok {
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("%s(%s)", method, goNumber(float32(f)))}, nil
+	}
+	if p, ok := lengthProperties[property]; ok {
+		call, err := lengthCall(p.stem, p.arg, value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{call}, nil
+	}
+	if stem, ok := shorthandProperties[property]; ok {
+		fields := strings.Fields(value)
+		if len(fields) == 1 {
+			call, err := lengthCall(stem, "yoga.EdgeAll", fields[0])
+			if err != nil {
+				return nil, err
+			}
+			return []string{call}, nil
+		}
+		// top, right, bottom, left with CSS fallbacks for missing values
+		for len(fields) < 4 {
+			fields = append(fields, fields[len(fields)-2])
+		}
+		var calls []string
+		for i, edge := range []string{"yoga.EdgeTop", "yoga.EdgeRight", "yoga.EdgeBottom", "yoga.EdgeLeft"} {
+			call, err := lengthCall(stem, edge, fields[i])
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, call)
+		}
+		return calls, nil
+	}
+	return nil, fmt.Errorf("no setter for style property: %s", property)
+}
+
+// lengthCall returns the setter call for a length value. arg is the edge or
+// gutter argument of the setter, if any.
+func lengthCall(stem, arg, value string) (string, error) {
+	v, err := yoga.ParseValue(value)
+	if err != nil {
+		return "", err
+	}
+	var args []string
+	if arg != "" {
+		args = append(args, arg)
+	}
+	suffix := ""
+	switch v.Unit {
+	case yoga.UnitUndefined, yoga.UnitPoint:
+		args = append(args, goNumber(v.Value))
+	case yoga.UnitPercent:
+		suffix = "Percent"
+		args = append(args, goNumber(v.Value))
+	default:
+		// auto, max-content, fit-content 和 stretch 有各自的 setter
+		suffix = exportName(v.Unit.String())
+	}
+	return fmt.Sprintf("Set%s%s(%s)", stem, suffix, strings.Join(args, ", ")), nil
+}
+
+func goNumber(f float32) string {
+	if yoga.IsNaN(f) {
+		return "yoga.Undefined"
+	}
+	return formatNumber(f)
+}
+
+// goEnum returns the Go name of an enum constant, e.g.
+// yoga.FlexDirectionRowReverse for "row-reverse"
+func goEnum(v fmt.Stringer) string {
+	name := v.String()
+	switch name {
+	case "ltr", "rtl":
+		name = strings.ToUpper(name)
+	}
+	return "yoga." + reflect.TypeOf(v).Name() + exportName(name)
+}
@@ -0,0 +1,181 @@
+package fixture
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+	"github.com/millken/yoga"
+)
+
+func TestParse(t *testing.T) {
+	r := assert.New(t)
+	cases, err := ParseFile("testdata/fixture.html")
+	r.Equal(nil, err)
+	r.Equal(3, len(cases))
+
+	c := cases[1]
+	r.Equal("contains_inner_text_long_word", c.Name)
+	r.Equal(1, len(c.Root.Children))
+	r.Equal(Layout{Left: 700, Top: 0, Width: 1300, Height: 10}, c.Root.Children[0].RTL)
+	r.True(strings.HasPrefix(c.Root.Children[0].Text, "Loremipsum"))
+
+	c = cases[2]
+	r.True(c.Disabled)
+	r.Equal([]yoga.ExperimentalFeature{yoga.ExperimentalFeatureWebFlexBasis}, c.Experiments)
+	r.Equal("item", c.Root.Children[0].ID)
+	r.Equal(yoga.Declaration{Property: "margin-start", Value: "auto"}, c.Root.Children[0].Style[0])
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		`<div style="width: 10px" data-layout-ltr="0 0 10 0" data-layout-rtl="0 0 10 0"></div>`,
+		`<div id="a" data-layout-ltr="0 0 10 0"></div>`,
+		`<div id="a" data-layout-ltr="0 0 10" data-layout-rtl="0 0 10 0"></div>`,
+		`<div id="a" style="colour: red" data-layout-ltr="0 0 0 0" data-layout-rtl="0 0 0 0"></div>`,
+		`<div id="a" data-layout-ltr="0 0 0 0" data-layout-rtl="0 0 0 0">`,
+		`<div id="a" data-layout-ltr="0 0 0 0" data-layout-rtl="0 0 0 0"><span></span></div>`,
+		`<div id="a" data-experiments="Teleport" data-layout-ltr="0 0 0 0" data-layout-rtl="0 0 0 0"></div>`,
+		`stray text`,
+	} {
+		if _, err := Parse(strings.NewReader(src)); err == nil {
+			t.Errorf("expected error for %s", src)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cases, err := ParseFile("testdata/fixture.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		if err := c.Verify(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	cases[0].Root.Children[1].RTL.Left = 1
+	if err := cases[0].Verify(); err == nil || !strings.Contains(err.Error(), "rtl root_child1") {
+		t.Errorf("expected mismatch of rtl root_child1, got %v", err)
+	}
+}
+
+// TestWriteTests compares the generated tests with the ones gentest
+// generated from the browser for the same fixtures
+func TestWriteTests(t *testing.T) {
+	cases, err := ParseFile("testdata/fixture.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTests(&buf, "gentest/fixture/testdata/fixture.html", cases); err != nil {
+		t.Fatal(err)
+	}
+	generated := buf.String()
+
+	for _, want := range []struct{ file, test string }{
+		{"../../tests/YGFlexTest_test.go", "TestFlexShrinkFlexGrowRow"},
+		{"../../tests/YGIntrinsicSizeTest_test.go", "TestContainsInnerTextLongWord"},
+	} {
+		src, err := os.ReadFile(want.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := testFunc(string(src), want.test)
+		if expected == "" {
+			t.Fatalf("%s not found in %s", want.test, want.file)
+		}
+		if actual := testFunc(generated, want.test); actual != expected {
+			t.Errorf("%s differs from gentest:\n%s", want.test, actual)
+		}
+	}
+
+	for _, line := range []string{
+		"\tt.Skip()\n",
+		"config.SetExperimentalFeatureEnabled(yoga.ExperimentalFeatureWebFlexBasis, true)",
+		"root_child0.SetMarginAuto(yoga.EdgeStart)",
+		"root_child0.SetPadding(yoga.EdgeTop, 1)",
+		"root_child0.SetPadding(yoga.EdgeLeft, 2)",
+		"root_child0.SetPositionPercent(yoga.EdgeAll, 5)",
+	} {
+		if !strings.Contains(generated, line) {
+			t.Errorf("generated tests are missing %q", line)
+		}
+	}
+}
+
+func TestSetterCalls(t *testing.T) {
+	r := assert.New(t)
+	e := &emitter{check: yoga.NewNode()}
+	defer e.check.Destroy()
+
+	calls, err := e.setterCalls(yoga.Declaration{Property: "margin", Value: "10% auto 5"})
+	r.Equal(nil, err)
+	r.Equal([]string{
+		"SetMarginPercent(yoga.EdgeTop, 10)",
+		"SetMarginAuto(yoga.EdgeRight)",
+		"SetMargin(yoga.EdgeBottom, 5)",
+		"SetMarginAuto(yoga.EdgeLeft)",
+	}, calls)
+	calls, err = e.setterCalls(yoga.Declaration{Property: "flex-wrap", Value: "nowrap"})
+	r.Equal(nil, err)
+	r.Equal([]string{"SetFlexWrap(yoga.WrapNoWrap)"}, calls)
+	calls, err = e.setterCalls(yoga.Declaration{Property: "min-width", Value: "fit-content"})
+	r.Equal(nil, err)
+	r.Equal([]string{"SetMinWidthFitContent()"}, calls)
+	_, err = e.setterCalls(yoga.Declaration{Property: "padding", Value: "auto"})
+	r.NotNil(err)
+
+	// 生成的 setter 必须存在于 yoga.Node 上，且每个样式属性都有对应的 setter
+	node := reflect.TypeOf(e.check)
+	properties := map[string][]string{}
+	for property := range enumProperties {
+		properties[property] = []string{"inherit", "column", "center", "absolute", "wrap", "hidden", "flex", "border-box", "ltr"}
+	}
+	for property := range numberProperties {
+		properties[property] = []string{"1"}
+	}
+	for property := range lengthProperties {
+		properties[property] = []string{"1", "1%", "undefined", "auto", "max-content", "fit-content", "stretch"}
+	}
+	for property := range shorthandProperties {
+		properties[property] = []string{"1", "1%", "auto", "1 2"}
+	}
+	for property, values := range properties {
+		r.True(yoga.IsStyleProperty(property))
+		supported := false
+		for _, value := range values {
+			calls, err := e.setterCalls(yoga.Declaration{Property: property, Value: value})
+			if err != nil {
+				continue
+			}
+			supported = true
+			for _, call := range calls {
+				method := call[:strings.Index(call, "(")]
+				if _, ok := node.MethodByName(method); !ok {
+					t.Errorf("%s: %s: no method %s", property, value, method)
+				}
+			}
+		}
+		if !supported {
+			t.Errorf("%s: no value accepted", property)
+		}
+	}
+}
+
+// testFunc extracts the source of a top level test function
+func testFunc(src, name string) string {
+	start := strings.Index(src, "func "+name+"(")
+	if start < 0 {
+		return ""
+	}
+	end := strings.Index(src[start:], "\n}\n")
+	if end < 0 {
+		return ""
+	}
+	return src[start : start+end+3]
+}
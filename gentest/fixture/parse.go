@@ -0,0 +1,349 @@
+// Package fixture reads gentest fixture HTML without a browser.
+//
+// A fixture is a list of top level divs, one per test case. The id of the
+// top level div names the test, data-experiments lists the experimental
+// features to enable and data-disabled="true" marks the test as skipped.
+// Styles are given inline with the property names understood by
+// yoga.SetStyleProperty, including the gentest extensions such as start,
+// margin-end or border-start-width. A leaf div with text gets the
+// intrinsic size measure func used by the generated tests.
+//
+// Since nothing computes the layout in a browser, every div records the
+// layout the browser would produce as "left top width height" for both
+// directions:
+//
+//	<div id="flex_grow_row" style="width: 100px; height: 100px; flex-direction: row"
+//	  data-layout-ltr="0 0 100 100" data-layout-rtl="0 0 100 100">
+//	  <div style="flex-grow: 1" data-layout-ltr="0 0 100 100" data-layout-rtl="0 0 100 100"></div>
+//	</div>
+package fixture
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/millken/yoga"
+)
+
+// Layout is the expected box of an element relative to its parent
+type Layout struct {
+	Left   float32
+	Top    float32
+	Width  float32
+	Height float32
+}
+
+// Element is a div of a fixture
+type Element struct {
+	ID       string
+	Style    []yoga.Declaration
+	Text     string
+	LTR      Layout
+	RTL      Layout
+	Children []*Element
+}
+
+// Case is a single test case, the top level div of a fixture
+type Case struct {
+	Name        string
+	Experiments []yoga.ExperimentalFeature
+	Disabled    bool
+	Root        *Element
+}
+
+// ParseFile reads the test cases of the fixture at path
+func ParseFile(path string) ([]*Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cases, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cases, nil
+}
+
+// Parse reads the test cases of a fixture
+func Parse(r io.Reader) ([]*Case, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: string(src), line: 1}
+	var cases []*Case
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokenEOF:
+			return cases, nil
+		case tokenText:
+			if strings.TrimSpace(tok.text) != "" {
+				return nil, p.errorf("unexpected text outside of a test case: %q", strings.TrimSpace(tok.text))
+			}
+		case tokenEnd:
+			return nil, p.errorf("unexpected </%s>", tok.name)
+		case tokenStart:
+			c, err := p.parseCase(tok)
+			if err != nil {
+				return nil, err
+			}
+			cases = append(cases, c)
+		}
+	}
+}
+
+func (p *parser) parseCase(tok token) (*Case, error) {
+	c := &Case{Name: tok.attrs["id"]}
+	if c.Name == "" {
+		return nil, p.errorf("test case without id")
+	}
+	for _, name := range strings.Fields(tok.attrs["data-experiments"]) {
+		feature, err := yoga.ExperimentalFeatureFromString(kebabCase(name))
+		if err != nil {
+			return nil, p.errorf("case %s: %v", c.Name, err)
+		}
+		c.Experiments = append(c.Experiments, feature)
+	}
+	c.Disabled = tok.attrs["data-disabled"] == "true"
+
+	root, err := p.parseElement(tok)
+	if err != nil {
+		return nil, fmt.Errorf("case %s: %w", c.Name, err)
+	}
+	c.Root = root
+	return c, nil
+}
+
+// parseElement reads the element opened by tok up to its end tag
+func (p *parser) parseElement(tok token) (*Element, error) {
+	if tok.name != "div" {
+		return nil, p.errorf("unsupported element <%s>", tok.name)
+	}
+	e := &Element{ID: tok.attrs["id"]}
+	var err error
+	if e.Style, err = yoga.ParseDeclarations(tok.attrs["style"]); err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	if e.LTR, err = parseLayout(tok.attrs, "data-layout-ltr"); err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	if e.RTL, err = parseLayout(tok.attrs, "data-layout-rtl"); err != nil {
+		return nil, p.errorf("%v", err)
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokenEOF:
+			return nil, p.errorf("missing </div>")
+		case tokenText:
+			text.WriteString(tok.text)
+		case tokenEnd:
+			if tok.name != "div" {
+				return nil, p.errorf("unexpected </%s>", tok.name)
+			}
+			// 和 innerText 一样只保留叶子节点的文本
+			if len(e.Children) == 0 {
+				e.Text = strings.Join(strings.Fields(text.String()), " ")
+			}
+			return e, nil
+		case tokenStart:
+			child, err := p.parseElement(tok)
+			if err != nil {
+				return nil, err
+			}
+			e.Children = append(e.Children, child)
+		}
+	}
+}
+
+// parseLayout reads a "left top width height" attribute
+func parseLayout(attrs map[string]string, name string) (Layout, error) {
+	value, ok := attrs[name]
+	if !ok {
+		return Layout{}, fmt.Errorf("missing %s", name)
+	}
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return Layout{}, fmt.Errorf("%s must be \"left top width height\": %s", name, value)
+	}
+	var values [4]float32
+	for i, field := range fields {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(field, "px"), 32)
+		if err != nil {
+			return Layout{}, fmt.Errorf("invalid %s: %s", name, value)
+		}
+		values[i] = float32(f)
+	}
+	return Layout{Left: values[0], Top: values[1], Width: values[2], Height: values[3]}, nil
+}
+
+// kebabCase turns the gentest experiment names such as WebFlexBasis into
+// the names understood by yoga.ExperimentalFeatureFromString
+func kebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenText
+	tokenStart
+	tokenEnd
+)
+
+type token struct {
+	kind  tokenKind
+	name  string
+	text  string
+	attrs map[string]string
+}
+
+// parser tokenizes the small HTML subset used by the fixtures: elements with
+// quoted or unquoted attributes, text and comments
+type parser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) advance(n int) string {
+	s := p.src[p.pos : p.pos+n]
+	p.line += strings.Count(s, "\n")
+	p.pos += n
+	return s
+}
+
+func (p *parser) next() (token, error) {
+	for {
+		rest := p.src[p.pos:]
+		switch {
+		case rest == "":
+			return token{kind: tokenEOF}, nil
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return token{}, p.errorf("unterminated comment")
+			}
+			p.advance(end + len("-->"))
+		case strings.HasPrefix(rest, "<!"):
+			// <!DOCTYPE html>
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return token{}, p.errorf("unterminated declaration")
+			}
+			p.advance(end + 1)
+		case strings.HasPrefix(rest, "</"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return token{}, p.errorf("unterminated end tag")
+			}
+			name := strings.ToLower(strings.TrimSpace(p.advance(end + 1)[2:end]))
+			return token{kind: tokenEnd, name: name}, nil
+		case rest[0] == '<':
+			return p.startTag()
+		default:
+			end := strings.IndexByte(rest, '<')
+			if end < 0 {
+				end = len(rest)
+			}
+			return token{kind: tokenText, text: html.UnescapeString(p.advance(end))}, nil
+		}
+	}
+}
+
+func (p *parser) startTag() (token, error) {
+	p.advance(1)
+	tok := token{kind: tokenStart, name: strings.ToLower(p.name()), attrs: make(map[string]string)}
+	if tok.name == "" {
+		return token{}, p.errorf("invalid start tag")
+	}
+	for {
+		p.skipSpace()
+		rest := p.src[p.pos:]
+		switch {
+		case rest == "":
+			return token{}, p.errorf("unterminated <%s>", tok.name)
+		case strings.HasPrefix(rest, "/>"):
+			return token{}, p.errorf("self-closing <%s/> is not supported", tok.name)
+		case rest[0] == '>':
+			p.advance(1)
+			return tok, nil
+		}
+
+		name := strings.ToLower(p.name())
+		if name == "" {
+			return token{}, p.errorf("invalid attribute in <%s>", tok.name)
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+			tok.attrs[name] = ""
+			continue
+		}
+		p.advance(1)
+		p.skipSpace()
+		value, err := p.attrValue()
+		if err != nil {
+			return token{}, err
+		}
+		tok.attrs[name] = html.UnescapeString(value)
+	}
+}
+
+func (p *parser) attrValue() (string, error) {
+	rest := p.src[p.pos:]
+	if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+		end := strings.IndexByte(rest[1:], rest[0])
+		if end < 0 {
+			return "", p.errorf("unterminated attribute value")
+		}
+		return p.advance(end + 2)[1 : end+1], nil
+	}
+	end := strings.IndexAny(rest, " \t\r\n>")
+	if end < 0 {
+		end = len(rest)
+	}
+	return p.advance(end), nil
+}
+
+func (p *parser) name() string {
+	end := p.pos
+	for end < len(p.src) && !strings.ContainsRune(" \t\r\n=>/", rune(p.src[end])) {
+		end++
+	}
+	return p.advance(end - p.pos)
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])) {
+		p.advance(1)
+	}
+}
@@ -0,0 +1,85 @@
+// Command fixturegen turns gentest fixtures into Go tests without a browser.
+//
+// The expected layouts are read from the data-layout-ltr and data-layout-rtl
+// attributes of the fixtures, see package fixture. Usage:
+//
+//	go run ./gentest/fixturegen -o tests gentest/fixtures/YGFlexTest.html
+//
+// With -check the fixtures are laid out with Yoga and compared against the
+// recorded layouts instead of generating tests.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/millken/yoga/gentest/fixture"
+)
+
+func main() {
+	out := flag.String("o", "tests", "directory to write the generated tests to")
+	check := flag.Bool("check", false, "compare the recorded layouts with Yoga instead of generating tests")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: fixturegen [-o dir] [-check] fixture.html...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		var err error
+		if *check {
+			err = checkFixture(path)
+		} else {
+			err = generate(path, *out)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func generate(path, out string) error {
+	cases, err := fixture.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := fixture.WriteTests(&buf, filepath.ToSlash(path), cases); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "_test.go"
+	return os.WriteFile(filepath.Join(out, name), buf.Bytes(), 0o644)
+}
+
+func checkFixture(path string) error {
+	cases, err := fixture.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, c := range cases {
+		if c.Disabled {
+			continue
+		}
+		if err := c.Verify(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s: %s", path, strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+// Package grid lays out Yoga nodes in rows and columns like a simplified
+// CSS grid.
+//
+// A Grid drives a Yoga node: its items become absolutely positioned
+// children whose positions and sizes are set from the computed tracks, and
+// the container gets the height of its rows when its height is auto. Items
+// and the container are ordinary Yoga nodes, so grids and flex subtrees
+// can be nested in each other. Lay out trees with grids with the
+// CalculateLayout of this package:
+//
+//	columns, _ := grid.ParseTracks("200 1fr 1fr")
+//	g := grid.New(container, columns, nil)
+//	g.ColumnGap, g.RowGap = 8, 8
+//	g.Add(sidebar, grid.Area{Row: 0, Column: 0, RowSpan: 2, ColumnSpan: 1})
+//	g.Add(card, grid.Span(1, 1))
+//	grid.CalculateLayout(root, 1024, yoga.Undefined, yoga.DirectionLTR, g)
+package grid
+
+import (
+	"slices"
+
+	"github.com/millken/yoga"
+)
+
+// Area is where an item is placed, rows and columns count from 0. A
+// negative row or column is placed automatically: in the first free area
+// of a row-major scan, or of the given row or column when the other is set.
+// Items in a given row that is full go to implicit auto columns. Spans
+// below 1 are 1.
+type Area struct {
+	Row, Column         int
+	RowSpan, ColumnSpan int
+}
+
+// Span returns an automatically placed area spanning rowSpan rows and
+// columnSpan columns
+func Span(rowSpan, columnSpan int) Area {
+	return Area{Row: -1, Column: -1, RowSpan: rowSpan, ColumnSpan: columnSpan}
+}
+
+// Cell returns the area of a single cell
+func Cell(row, column int) Area {
+	return Area{Row: row, Column: column, RowSpan: 1, ColumnSpan: 1}
+}
+
+// Item is a child of a grid
+type Item struct {
+	Node *yoga.Node
+	Area Area
+	// stretchWidth 和 stretchHeight 记录加入时宽高是否为 auto，这样的项目
+	// 会被拉伸到所在区域
+	stretchWidth, stretchHeight bool
+	// positionType 是加入前的定位方式，移除时恢复
+	positionType yoga.PositionType
+	// placed 是自动放置之后的区域
+	placed Area
+}
+
+// Placed returns the area of the item after automatic placement, as of
+// the last layout
+func (it *Item) Placed() Area {
+	return it.placed
+}
+
+// Grid lays out the children of a node in tracks
+type Grid struct {
+	Node    *yoga.Node
+	Columns []Track
+	// Rows are the explicit rows, rows needed beyond them are auto
+	Rows              []Track
+	RowGap, ColumnGap float32
+
+	items      []*Item
+	autoHeight bool
+	// 上一次布局得到的轨道尺寸
+	columns  []float32
+	rows     []float32
+	rowCount int
+}
+
+// New creates a grid driving node. Children must be added with Add; when
+// the height of node is auto it is set to the height of the rows on layout.
+func New(node *yoga.Node, columns, rows []Track) *Grid {
+	unit := node.GetHeight().Unit
+	return &Grid{
+		Node:       node,
+		Columns:    columns,
+		Rows:       rows,
+		autoHeight: unit == yoga.UnitAuto || unit == yoga.UnitUndefined,
+	}
+}
+
+// Add appends child to the node of the grid and places it in area. Items
+// with an auto width or height are stretched to their area, others keep
+// their size and are aligned to its top left.
+func (g *Grid) Add(child *yoga.Node, area Area) *Item {
+	area.RowSpan, area.ColumnSpan = max(area.RowSpan, 1), max(area.ColumnSpan, 1)
+	isAuto := func(v yoga.Value) bool {
+		return v.Unit == yoga.UnitAuto || v.Unit == yoga.UnitUndefined
+	}
+	it := &Item{
+		Node:          child,
+		Area:          area,
+		stretchWidth:  isAuto(child.GetWidth()),
+		stretchHeight: isAuto(child.GetHeight()),
+		positionType:  child.GetPositionType(),
+	}
+	child.SetPositionType(yoga.PositionTypeAbsolute)
+	g.Node.InsertChild(child, g.Node.GetChildCount())
+	g.items = append(g.items, it)
+	return it
+}
+
+// Remove removes the item and its node from the grid. The node gets back
+// its position type, its positions are cleared and stretched sizes are
+// auto again, so it can be inserted elsewhere.
+func (g *Grid) Remove(it *Item) {
+	if i := slices.Index(g.items, it); i >= 0 {
+		g.items = slices.Delete(g.items, i, i+1)
+		g.Node.RemoveChild(it.Node)
+		n := it.Node
+		n.SetPositionType(it.positionType)
+		n.SetPosition(yoga.EdgeLeft, yoga.Undefined)
+		n.SetPosition(yoga.EdgeTop, yoga.Undefined)
+		if it.stretchWidth {
+			n.SetWidthAuto()
+		}
+		if it.stretchHeight {
+			n.SetHeightAuto()
+		}
+	}
+}
+
+// Items returns the items of the grid
+func (g *Grid) Items() []*Item {
+	return g.items
+}
+
+// ColumnSizes returns the sizes of the columns as of the last layout,
+// including implicit ones
+func (g *Grid) ColumnSizes() []float32 {
+	return g.columns
+}
+
+// RowSizes returns the sizes of the rows as of the last layout, including
+// implicit ones
+func (g *Grid) RowSizes() []float32 {
+	return g.rows
+}
+
+// place resolves the areas of the items and returns the number of rows and
+// columns they need
+func (g *Grid) place() (rows, columns int) {
+	columns = len(g.Columns)
+	for _, it := range g.items {
+		if it.Area.Column >= 0 {
+			columns = max(columns, it.Area.Column+it.Area.ColumnSpan)
+		} else {
+			columns = max(columns, it.Area.ColumnSpan)
+		}
+	}
+	rows = len(g.Rows)
+	occupied := make(map[[2]int]bool)
+	fits := func(a Area) bool {
+		for r := a.Row; r < a.Row+a.RowSpan; r++ {
+			for c := a.Column; c < a.Column+a.ColumnSpan; c++ {
+				if occupied[[2]int{r, c}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	occupy := func(it *Item, a Area) {
+		it.placed = a
+		for r := a.Row; r < a.Row+a.RowSpan; r++ {
+			for c := a.Column; c < a.Column+a.ColumnSpan; c++ {
+				occupied[[2]int{r, c}] = true
+			}
+		}
+		rows = max(rows, a.Row+a.RowSpan)
+	}
+
+	// 先放置指定了行和列的项目，再依次自动放置其余项目
+	for _, it := range g.items {
+		if it.Area.Row >= 0 && it.Area.Column >= 0 {
+			occupy(it, it.Area)
+		}
+	}
+	cursorRow, cursorColumn := 0, 0
+	for _, it := range g.items {
+		a := it.Area
+		switch {
+		case a.Row >= 0 && a.Column >= 0:
+			continue
+		case a.Row >= 0:
+			// 取行中第一个放得下的位置，放不下时延伸到隐式列
+			for a.Column = 0; !fits(a); a.Column++ {
+			}
+			columns = max(columns, a.Column+a.ColumnSpan)
+		case a.Column >= 0:
+			for a.Row = 0; !fits(a); a.Row++ {
+			}
+		default:
+			for a.Row, a.Column = cursorRow, cursorColumn; ; {
+				if a.Column+a.ColumnSpan > columns {
+					a.Row, a.Column = a.Row+1, 0
+					continue
+				}
+				if fits(a) {
+					break
+				}
+				a.Column++
+			}
+			cursorRow, cursorColumn = a.Row, a.Column+a.ColumnSpan
+		}
+		occupy(it, a)
+	}
+	return rows, columns
+}
+
+// inner returns the offset of the content box from the padding box and
+// the size of the content box
+func inner(n *yoga.Node) (left, top, width, height float32) {
+	left = n.GetComputedPadding(yoga.EdgeLeft)
+	top = n.GetComputedPadding(yoga.EdgeTop)
+	width = n.GetComputedWidth() - left - n.GetComputedPadding(yoga.EdgeRight) -
+		n.GetComputedBorder(yoga.EdgeLeft) - n.GetComputedBorder(yoga.EdgeRight)
+	height = n.GetComputedHeight() - top - n.GetComputedPadding(yoga.EdgeBottom) -
+		n.GetComputedBorder(yoga.EdgeTop) - n.GetComputedBorder(yoga.EdgeBottom)
+	return left, top, max(width, 0), max(height, 0)
+}
+
+// offsets returns the start of every track
+func offsets(sizes []float32, gap float32) []float32 {
+	starts := make([]float32, len(sizes)+1)
+	for i, size := range sizes {
+		starts[i+1] = starts[i] + size + gap
+	}
+	return starts
+}
+
+// span returns the start and size of tracks [start, start+n)
+func span(starts []float32, gap float32, start, n int) (float32, float32) {
+	return starts[start], starts[start+n] - starts[start] - gap
+}
+
+// resetItems sets stretched items back to an auto size, so the next
+// layout measures their content
+func (g *Grid) resetItems() {
+	for _, it := range g.items {
+		if it.stretchWidth {
+			it.Node.SetWidthAuto()
+		}
+		if it.stretchHeight {
+			it.Node.SetHeightAuto()
+		}
+	}
+}
+
+// sizeColumns sizes the columns from the measured widths of the items and
+// stretches the items to their columns
+func (g *Grid) sizeColumns() {
+	rows, columns := g.place()
+	var items []contribution
+	for _, it := range g.items {
+		n := it.Node
+		width := n.GetComputedWidth() + n.GetComputedMargin(yoga.EdgeLeft) + n.GetComputedMargin(yoga.EdgeRight)
+		items = append(items, contribution{start: it.placed.Column, span: it.placed.ColumnSpan, size: width})
+	}
+	left, _, width, _ := inner(g.Node)
+	g.columns = sizeTracks(g.Columns, columns, width, g.ColumnGap, items)
+	g.rowCount = rows
+
+	rtl := g.Node.GetLayoutDirection() == yoga.DirectionRTL
+	starts := offsets(g.columns, g.ColumnGap)
+	for _, it := range g.items {
+		x, w := span(starts, g.ColumnGap, it.placed.Column, it.placed.ColumnSpan)
+		n := it.Node
+		margins := n.GetComputedMargin(yoga.EdgeLeft) + n.GetComputedMargin(yoga.EdgeRight)
+		if it.stretchWidth {
+			n.SetWidth(max(w-margins, 0))
+		}
+		if rtl {
+			// 列从右向左排列，项目对齐到区域的右边
+			itemWidth := w
+			if !it.stretchWidth {
+				itemWidth = n.GetComputedWidth() + margins
+			}
+			x = width - x - itemWidth
+		}
+		n.SetPosition(yoga.EdgeLeft, left+x)
+	}
+}
+
+// sizeRows sizes the rows from the measured heights of the items, places
+// the items and sets the height of an auto height container
+func (g *Grid) sizeRows() {
+	var items []contribution
+	for _, it := range g.items {
+		n := it.Node
+		height := n.GetComputedHeight() + n.GetComputedMargin(yoga.EdgeTop) + n.GetComputedMargin(yoga.EdgeBottom)
+		items = append(items, contribution{start: it.placed.Row, span: it.placed.RowSpan, size: height})
+	}
+	_, top, _, height := inner(g.Node)
+	if g.autoHeight {
+		height = yoga.Undefined
+	}
+	g.rows = sizeTracks(g.Rows, g.rowCount, height, g.RowGap, items)
+
+	starts := offsets(g.rows, g.RowGap)
+	for _, it := range g.items {
+		y, h := span(starts, g.RowGap, it.placed.Row, it.placed.RowSpan)
+		n := it.Node
+		if it.stretchHeight {
+			n.SetHeight(max(h-n.GetComputedMargin(yoga.EdgeTop)-n.GetComputedMargin(yoga.EdgeBottom), 0))
+		}
+		n.SetPosition(yoga.EdgeTop, top+y)
+	}
+	if g.autoHeight {
+		total := float32(0)
+		if len(g.rows) > 0 {
+			total = starts[len(g.rows)] - g.RowGap
+		}
+		n := g.Node
+		n.SetHeight(total + n.GetComputedPadding(yoga.EdgeTop) + n.GetComputedPadding(yoga.EdgeBottom) +
+			n.GetComputedBorder(yoga.EdgeTop) + n.GetComputedBorder(yoga.EdgeBottom))
+	}
+}
+
+// CalculateLayout lays out the tree of root like root.CalculateLayout
+// together with the grids in it, which must be listed outer grids first.
+//
+// Each round lays out the tree three times: with stretched items at their
+// content size to size the columns, with the items stretched to their
+// columns to size the rows, and with the final positions. Rounds repeat
+// while track sizes change, which happens when grids are nested, at most
+// once per grid.
+func CalculateLayout(root *yoga.Node, width, height float32, direction yoga.Direction, grids ...*Grid) {
+	for round := 0; round <= len(grids); round++ {
+		before := make([][]float32, 0, 2*len(grids))
+		for _, g := range grids {
+			before = append(before, slices.Clone(g.columns), slices.Clone(g.rows))
+			g.resetItems()
+		}
+		root.CalculateLayout(width, height, direction)
+		for _, g := range grids {
+			g.sizeColumns()
+		}
+		root.CalculateLayout(width, height, direction)
+		changed := false
+		for i, g := range grids {
+			g.sizeRows()
+			if !slices.Equal(before[2*i], g.columns) || !slices.Equal(before[2*i+1], g.rows) {
+				changed = true
+			}
+		}
+		root.CalculateLayout(width, height, direction)
+		if !changed {
+			return
+		}
+	}
+}
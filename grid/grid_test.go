@@ -0,0 +1,135 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+	"github.com/millken/yoga"
+)
+
+func TestParseTracks(t *testing.T) {
+	r := assert.New(t)
+	tracks, err := ParseTracks("200px 1fr 25% auto 1.5fr")
+	r.Equal(nil, err)
+	r.Equal([]Track{Fixed(200), Fr(1), Percent(25), Auto(), Fr(1.5)}, tracks)
+	var s []string
+	for _, track := range tracks {
+		s = append(s, track.String())
+	}
+	r.Equal([]string{"200", "1fr", "25%", "auto", "1.5fr"}, s)
+	_, err = ParseTracks("1fr x")
+	r.NotNil(err)
+}
+
+func TestSizeTracks(t *testing.T) {
+	r := assert.New(t)
+	items := []contribution{{start: 1, span: 1, size: 80}, {start: 0, span: 2, size: 200}}
+	// 固定尺寸之外的空间按 fr 分配
+	r.Equal([]float32{100, 100, 200}, sizeTracks([]Track{Fixed(100), Fr(1), Fr(2)}, 3, 420, 10, items))
+	// 没有 fr 轨道时拉伸 auto 轨道
+	r.Equal([]float32{145, 145}, sizeTracks([]Track{Auto(), Auto()}, 2, 300, 10, nil))
+	r.Equal([]float32{20, 180}, sizeTracks([]Track{Percent(10), Auto()}, 2, 200, 0, []contribution{{start: 1, span: 1, size: 80}}))
+	// 不确定尺寸时 fr 轨道按内容保持比例
+	r.Equal([]float32{80, 160}, sizeTracks([]Track{Fr(1), Fr(2)}, 2, yoga.Undefined, 0, []contribution{{start: 0, span: 1, size: 80}}))
+	// 跨轨道的项目把不足的部分分给 auto 轨道
+	r.Equal([]float32{50, 115, 30}, sizeTracks([]Track{Fixed(50), Auto(), Fixed(30)}, 3, yoga.Undefined, 5, []contribution{{start: 0, span: 3, size: 205}}))
+}
+
+func TestGrid(t *testing.T) {
+	r := assert.New(t)
+	root := yoga.NewNode()
+	root.SetWidth(450)
+	root.SetPadding(yoga.EdgeAll, 10)
+	container := yoga.NewNode()
+	container.SetBorder(yoga.EdgeAll, 1)
+	container.SetPadding(yoga.EdgeAll, 4)
+	root.InsertChild(container, 0)
+	footer := yoga.NewNode()
+	footer.SetHeight(20)
+	root.InsertChild(footer, 1)
+
+	columns, _ := ParseTracks("100 1fr 2fr")
+	g := New(container, columns, nil)
+	g.RowGap, g.ColumnGap = 10, 10
+
+	header := yoga.NewNode()
+	header.SetHeight(30)
+	g.Add(header, Area{Row: 0, Column: 0, RowSpan: 1, ColumnSpan: 3})
+	sidebar := yoga.NewNode()
+	g.Add(sidebar, Area{Row: 1, Column: 0, RowSpan: 2, ColumnSpan: 1})
+	text := yoga.NewNode()
+	text.SetMeasureFunc(func(width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+		return yoga.Size{Width: 50, Height: 40}
+	})
+	a := g.Add(text, Span(1, 1))
+	wide := yoga.NewNode()
+	wide.SetHeight(25)
+	b := g.Add(wide, Span(1, 2))
+	defer func() {
+		for _, n := range []*yoga.Node{wide, text, sidebar, header, footer, container, root} {
+			n.Destroy()
+		}
+	}()
+
+	CalculateLayout(root, yoga.Undefined, yoga.Undefined, yoga.DirectionLTR, g)
+	r.Equal(Area{Row: 1, Column: 1, RowSpan: 1, ColumnSpan: 1}, a.Placed())
+	r.Equal(Area{Row: 2, Column: 1, RowSpan: 1, ColumnSpan: 2}, b.Placed())
+	r.Equal([]float32{100, 100, 200}, g.ColumnSizes())
+	r.Equal([]float32{30, 40, 25}, g.RowSizes())
+
+	rect := func(n *yoga.Node) [4]float32 {
+		return [4]float32{n.GetComputedLeft(), n.GetComputedTop(), n.GetComputedWidth(), n.GetComputedHeight()}
+	}
+	r.Equal([4]float32{5, 5, 420, 30}, rect(header))
+	r.Equal([4]float32{5, 45, 100, 75}, rect(sidebar))
+	r.Equal([4]float32{115, 45, 100, 40}, rect(text))
+	r.Equal([4]float32{115, 95, 310, 25}, rect(wide))
+	r.Equal(float32(125), container.GetComputedHeight())
+	r.Equal(float32(135), footer.GetComputedTop())
+
+	CalculateLayout(root, yoga.Undefined, yoga.Undefined, yoga.DirectionRTL, g)
+	r.Equal([4]float32{215, 45, 100, 40}, rect(text))
+	r.Equal([4]float32{5, 95, 310, 25}, rect(wide))
+
+	g.Remove(b)
+	r.Equal(uint32(3), container.GetChildCount())
+	r.Equal(yoga.PositionTypeRelative, wide.GetPositionType())
+	r.Equal(yoga.UnitUndefined, wide.GetPosition(yoga.EdgeLeft).Unit)
+	r.Equal(yoga.UnitUndefined, wide.GetPosition(yoga.EdgeTop).Unit)
+	r.Equal(yoga.UnitAuto, wide.GetWidth().Unit)
+	r.Equal(yoga.Pt(25), wide.GetHeight())
+	CalculateLayout(root, yoga.Undefined, yoga.Undefined, yoga.DirectionLTR, g)
+	r.Equal([]float32{30, 40, 0}, g.RowSizes())
+}
+
+func TestPlaceInRow(t *testing.T) {
+	r := assert.New(t)
+	container := yoga.NewNode()
+	container.SetWidth(300)
+	g := New(container, []Track{Fixed(100), Fixed(100)}, nil)
+	var nodes []*yoga.Node
+	add := func(area Area) *Item {
+		n := yoga.NewNode()
+		n.SetHeight(10)
+		nodes = append(nodes, n)
+		return g.Add(n, area)
+	}
+	defer func() {
+		for _, n := range nodes {
+			n.Destroy()
+		}
+		container.Destroy()
+	}()
+	add(Cell(0, 0))
+	last := add(Area{Row: 0, Column: -1})
+	full := add(Area{Row: 0, Column: -1})
+	wide := add(Area{Row: 1, Column: -1, ColumnSpan: 2})
+
+	CalculateLayout(container, yoga.Undefined, yoga.Undefined, yoga.DirectionLTR, g)
+	// 行中最后一列也会被检查，都放不下时加一个隐式列
+	r.Equal(Area{Row: 0, Column: 1, RowSpan: 1, ColumnSpan: 1}, last.Placed())
+	r.Equal(Area{Row: 0, Column: 2, RowSpan: 1, ColumnSpan: 1}, full.Placed())
+	r.Equal(Area{Row: 1, Column: 0, RowSpan: 1, ColumnSpan: 2}, wide.Placed())
+	r.Equal(3, len(g.ColumnSizes()))
+	r.Equal(float32(200), full.Node.GetComputedLeft())
+}
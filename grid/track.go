@@ -0,0 +1,190 @@
+package grid
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/millken/yoga"
+)
+
+// TrackKind is how the size of a track is determined
+type TrackKind int
+
+const (
+	// TrackFixed is a size in points
+	TrackFixed TrackKind = iota
+	// TrackPercent is a percentage of the inner size of the container
+	TrackPercent
+	// TrackFr is a share of the free space
+	TrackFr
+	// TrackAuto fits the items in the track
+	TrackAuto
+)
+
+// Track is the size of a row or column
+type Track struct {
+	Kind  TrackKind
+	Value float32
+}
+
+// Fixed returns a track of v points
+func Fixed(v float32) Track { return Track{Kind: TrackFixed, Value: v} }
+
+// Percent returns a track of v percent of the container
+func Percent(v float32) Track { return Track{Kind: TrackPercent, Value: v} }
+
+// Fr returns a track taking v shares of the free space
+func Fr(v float32) Track { return Track{Kind: TrackFr, Value: v} }
+
+// Auto returns a track sized by its items
+func Auto() Track { return Track{Kind: TrackAuto} }
+
+// String formats the track like CSS, e.g. "100", "25%", "1fr" or "auto"
+func (t Track) String() string {
+	v := strconv.FormatFloat(float64(t.Value), 'g', -1, 32)
+	switch t.Kind {
+	case TrackPercent:
+		return v + "%"
+	case TrackFr:
+		return v + "fr"
+	case TrackAuto:
+		return "auto"
+	}
+	return v
+}
+
+// ParseTracks parses a space separated track list such as
+// "200 1fr 25% auto", sizes in points may have a px suffix
+func ParseTracks(s string) ([]Track, error) {
+	var tracks []Track
+	for _, field := range strings.Fields(s) {
+		if field == "auto" {
+			tracks = append(tracks, Auto())
+			continue
+		}
+		kind, num := TrackFixed, strings.TrimSuffix(field, "px")
+		switch {
+		case strings.HasSuffix(field, "fr"):
+			kind, num = TrackFr, strings.TrimSuffix(field, "fr")
+		case strings.HasSuffix(field, "%"):
+			kind, num = TrackPercent, strings.TrimSuffix(field, "%")
+		}
+		v, err := strconv.ParseFloat(num, 32)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid track %q", field)
+		}
+		tracks = append(tracks, Track{Kind: kind, Value: float32(v)})
+	}
+	return tracks, nil
+}
+
+// contribution is the size an item needs along an axis
+type contribution struct {
+	start, span int
+	size        float32
+}
+
+// sizeTracks sizes count tracks along an axis, tracks beyond the defined
+// ones are auto. With a definite available size percentages resolve
+// against it, fr tracks share the free space without regard to their items
+// like minmax(0, 1fr), and without fr tracks auto tracks are stretched to
+// fill it. With an undefined size percentages act like auto and fr tracks
+// are sized by their items, keeping their ratio.
+func sizeTracks(tracks []Track, count int, avail, gap float32, items []contribution) []float32 {
+	track := func(i int) Track {
+		if i < len(tracks) {
+			return tracks[i]
+		}
+		return Auto()
+	}
+	definite := !yoga.IsNaN(avail)
+	sizes := make([]float32, count)
+	for i := range sizes {
+		switch t := track(i); t.Kind {
+		case TrackFixed:
+			sizes[i] = t.Value
+		case TrackPercent:
+			if definite {
+				sizes[i] = avail * t.Value / 100
+			}
+		}
+	}
+	intrinsic := func(i int) bool {
+		switch track(i).Kind {
+		case TrackAuto:
+			return true
+		case TrackFr, TrackPercent:
+			return !definite
+		}
+		return false
+	}
+
+	// 按跨度从小到大处理，跨多个轨道的项目把不足的尺寸均分给自适应轨道
+	items = slices.Clone(items)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].span < items[j].span })
+	for _, it := range items {
+		if it.span == 1 {
+			if intrinsic(it.start) {
+				sizes[it.start] = max(sizes[it.start], it.size)
+			}
+			continue
+		}
+		sum := gap * float32(it.span-1)
+		var grow []int
+		for i := it.start; i < it.start+it.span; i++ {
+			sum += sizes[i]
+			if intrinsic(i) {
+				grow = append(grow, i)
+			}
+		}
+		if extra := it.size - sum; extra > 0 && len(grow) > 0 {
+			for _, i := range grow {
+				sizes[i] += extra / float32(len(grow))
+			}
+		}
+	}
+
+	var used, fr float32
+	var autos []int
+	for i, size := range sizes {
+		used += size
+		switch track(i).Kind {
+		case TrackFr:
+			fr += track(i).Value
+		case TrackAuto:
+			autos = append(autos, i)
+		}
+	}
+	if count > 0 {
+		used += gap * float32(count-1)
+	}
+	switch {
+	case definite && fr > 0:
+		free := max(avail-used, 0) / max(fr, 1)
+		for i := range sizes {
+			if t := track(i); t.Kind == TrackFr {
+				sizes[i] = free * t.Value
+			}
+		}
+	case definite && avail > used && len(autos) > 0:
+		for _, i := range autos {
+			sizes[i] += (avail - used) / float32(len(autos))
+		}
+	case !definite && fr > 0:
+		var perFr float32
+		for i, size := range sizes {
+			if t := track(i); t.Kind == TrackFr && t.Value > 0 {
+				perFr = max(perFr, size/t.Value)
+			}
+		}
+		for i := range sizes {
+			if t := track(i); t.Kind == TrackFr {
+				sizes[i] = perFr * t.Value
+			}
+		}
+	}
+	return sizes
+}
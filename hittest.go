@@ -0,0 +1,160 @@
+package yoga
+
+import "strconv"
+
+// Rect is an axis-aligned rectangle
+type Rect struct {
+	X, Y          float32
+	Width, Height float32
+}
+
+// Contains reports whether the point x, y lies inside r, the right and
+// bottom edges are exclusive
+func (r Rect) Contains(x, y float32) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.Width && y < r.Y+r.Height
+}
+
+// Intersect returns the intersection of r and o, which is empty when they
+// do not overlap
+func (r Rect) Intersect(o Rect) Rect {
+	x0, y0 := max(r.X, o.X), max(r.Y, o.Y)
+	x1, y1 := min(r.X+r.Width, o.X+o.Width), min(r.Y+r.Height, o.Y+o.Height)
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{X: x0, Y: y0}
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Empty reports whether r has no area
+func (r Rect) Empty() bool {
+	return !(r.Width > 0 && r.Height > 0)
+}
+
+// SetScrollOffset sets how far the content of the node is scrolled, its
+// children are moved up and left by x and y in AbsoluteRect and hit testing
+func (n *Node) SetScrollOffset(x, y float32) {
+	if n.node == nil {
+		return
+	}
+	attrs := getNodeAttrs(n.node, true)
+	attrs.scrollX, attrs.scrollY = x, y
+}
+
+// GetScrollOffset gets the scroll offset of the node
+func (n *Node) GetScrollOffset() (x, y float32) {
+	if attrs := getNodeAttrs(n.node, false); attrs != nil {
+		return attrs.scrollX, attrs.scrollY
+	}
+	return 0, 0
+}
+
+// AbsoluteRect returns the border box of the node in the coordinates of
+// the root's parent, with the scroll offsets of its ancestors applied
+func (n *Node) AbsoluteRect() Rect {
+	if n.node == nil {
+		return Rect{}
+	}
+	r := Rect{X: n.GetComputedLeft(), Y: n.GetComputedTop(), Width: n.GetComputedWidth(), Height: n.GetComputedHeight()}
+	for p := n.GetParent(); p != nil; p = p.GetParent() {
+		scrollX, scrollY := p.GetScrollOffset()
+		r.X += p.GetComputedLeft() - scrollX
+		r.Y += p.GetComputedTop() - scrollY
+	}
+	return r
+}
+
+// NodeAt returns the deepest node under the point x, y in the coordinates
+// of the root's parent, and its index path such as "0/2" ("" for the root).
+// Later siblings are on top of earlier ones. Nodes with display none are
+// skipped, children of nodes with overflow hidden or scroll are clipped to
+// their padding box. NodeAt returns nil when no node is hit.
+func (n *Node) NodeAt(x, y float32) (*Node, string) {
+	if n.node == nil {
+		return nil, ""
+	}
+	var hit *Node
+	var hitPath string
+	walkHitBoxes(n, true, func(node *Node, path string, box, visible Rect) bool {
+		if visible.Contains(x, y) {
+			// 后序遍历中第一个命中的就是最深、最上层的节点
+			hit, hitPath = node, path
+			return false
+		}
+		return true
+	})
+	return hit, hitPath
+}
+
+// NodesInRect returns the nodes whose visible part overlaps r in pre-order,
+// with the same rules as NodeAt
+func (n *Node) NodesInRect(r Rect) []*Node {
+	if n.node == nil {
+		return nil
+	}
+	var nodes []*Node
+	walkHitBoxes(n, false, func(node *Node, path string, box, visible Rect) bool {
+		if !visible.Intersect(r).Empty() {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// walkHitBoxes calls visit with the border box and its visible part for
+// root and its descendants, in pre-order or in reverse post-order (top-most
+// node first) when topFirst is set. The walk stops when visit returns false.
+func walkHitBoxes(root *Node, topFirst bool, visit func(n *Node, path string, box, visible Rect) bool) {
+	var walk func(n *Node, path string, originX, originY float32, clip *Rect) bool
+	walk = func(n *Node, path string, originX, originY float32, clip *Rect) bool {
+		if n.GetDisplay() == DisplayNone {
+			return true
+		}
+		box := Rect{
+			X:      originX + n.GetComputedLeft(),
+			Y:      originY + n.GetComputedTop(),
+			Width:  n.GetComputedWidth(),
+			Height: n.GetComputedHeight(),
+		}
+		visible := box
+		if clip != nil {
+			visible = box.Intersect(*clip)
+		}
+		if !topFirst && !visit(n, path, box, visible) {
+			return false
+		}
+
+		childClip := clip
+		if n.GetOverflow() != OverflowVisible {
+			b := edges(n.GetComputedBorder)
+			padding := Rect{X: box.X + b[0], Y: box.Y + b[1], Width: box.Width - b[0] - b[2], Height: box.Height - b[1] - b[3]}
+			if clip != nil {
+				padding = padding.Intersect(*clip)
+			}
+			childClip = &padding
+		}
+		scrollX, scrollY := n.GetScrollOffset()
+		childCount := n.GetChildCount()
+		for j := uint32(0); j < childCount; j++ {
+			i := j
+			if topFirst {
+				i = childCount - 1 - j
+			}
+			childPath := strconv.Itoa(int(i))
+			if path != "" {
+				childPath = path + "/" + childPath
+			}
+			if !walk(n.GetChild(i), childPath, box.X-scrollX, box.Y-scrollY, childClip) {
+				return false
+			}
+		}
+
+		if topFirst {
+			return visit(n, path, box, visible)
+		}
+		return true
+	}
+	// 子树也使用整棵树的坐标系
+	abs := root.AbsoluteRect()
+	walk(root, "", abs.X-root.GetComputedLeft(), abs.Y-root.GetComputedTop(), nil)
+}
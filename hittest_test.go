@@ -0,0 +1,66 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestHitTest(t *testing.T) {
+	r := assert.New(t)
+	root, nodes := Build(nil, Column(Width(100), Height(100),
+		Box(ID("header"), Height(20), PaddingEdge(EdgeLeft, 10),
+			Box(ID("button"), Width(30), Height(10)),
+		),
+		Box(ID("list"), Height(40), OverflowOption(OverflowScroll), BorderEdge(EdgeTop, 2),
+			Box(ID("item0"), Height(30)),
+			Box(ID("item1"), Height(30)),
+		),
+		Box(ID("hidden"), Height(40), DisplayOption(DisplayNone)),
+		Box(ID("overlay"), Absolute(), Position(EdgeLeft, 80), Position(EdgeTop, 90), Width(40), Height(40)),
+	))
+	defer root.FreeRecursive()
+	nodes["list"].SetScrollOffset(0, 25)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	r.Equal(Rect{X: 10, Y: 0, Width: 30, Height: 10}, nodes["button"].AbsoluteRect())
+	r.Equal(Rect{X: 0, Y: 27, Width: 100, Height: 30}, nodes["item1"].AbsoluteRect())
+
+	hitID := func(x, y float32) (string, string) {
+		n, path := root.NodeAt(x, y)
+		if n == nil {
+			return "", path
+		}
+		return n.GetID(), path
+	}
+	id, path := hitID(15, 5)
+	r.Equal("button", id)
+	r.Equal("0/0", path)
+	id, _ = hitID(5, 5)
+	r.Equal("header", id)
+	// item0 被滚动到 list 上方，被裁剪
+	id, _ = hitID(50, 21)
+	r.Equal("list", id)
+	id, path = hitID(50, 30)
+	r.Equal("item1", id)
+	r.Equal("1/1", path)
+	id, path = hitID(50, 70)
+	r.Equal("", id)
+	r.Equal("", path)
+	id, _ = hitID(85, 95)
+	r.Equal("overlay", id)
+	id, _ = hitID(110, 110)
+	r.Equal("overlay", id)
+	id, _ = hitID(130, 130)
+	r.Equal("", id)
+
+	var ids []string
+	for _, n := range root.NodesInRect(Rect{X: 0, Y: 15, Width: 50, Height: 10}) {
+		ids = append(ids, n.GetID())
+	}
+	r.Equal([]string{"", "header", "list", "item0"}, ids)
+
+	hit, path := nodes["list"].NodeAt(50, 30)
+	r.Equal("item1", hit.GetID())
+	r.Equal("1", path)
+}
@@ -0,0 +1,257 @@
+package yoga
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// HTMLMode selects how ExportHTML lays out the exported boxes
+type HTMLMode int
+
+const (
+	// HTMLModeFlex writes nested divs with the equivalent CSS flexbox styles,
+	// so the browser computes the layout, like the gentest fixtures
+	HTMLModeFlex HTMLMode = iota
+	// HTMLModeAbsolute places every div at Yoga's computed coordinates
+	HTMLModeAbsolute
+)
+
+// htmlFlexCSS mirrors the defaults of gentest/test-template.html, which make
+// browser flexbox behave like Yoga
+const htmlFlexCSS = `    body {
+      padding: 0;
+      margin: 0;
+    }
+
+    div {
+      box-sizing: border-box;
+      position: relative;
+      border: 0 solid black;
+      margin: 0;
+      padding: 0;
+      display: flex;
+      flex-direction: column;
+      align-items: stretch;
+      align-content: flex-start;
+      justify-content: flex-start;
+      flex-shrink: 0;
+    }
+
+    body>div {
+      position: absolute;
+    }
+`
+
+const htmlAbsoluteCSS = `    body {
+      padding: 0;
+      margin: 0;
+    }
+
+    div {
+      position: absolute;
+      box-sizing: border-box;
+      border: 0 solid #1f5fbf;
+      outline: 1px dashed rgba(31, 95, 191, 0.5);
+      background: rgba(31, 95, 191, 0.06);
+    }
+
+    div[data-overflow] {
+      outline-color: #d33;
+    }
+`
+
+// cssProperties maps the Yoga specific property names produced by
+// styleDeclarations to standard CSS properties
+var cssProperties = map[string]string{
+	"start":                   "inset-inline-start",
+	"end":                     "inset-inline-end",
+	"inset-horizontal":        "inset-inline",
+	"inset-vertical":          "inset-block",
+	"margin-start":            "margin-inline-start",
+	"margin-end":              "margin-inline-end",
+	"margin-horizontal":       "margin-inline",
+	"margin-vertical":         "margin-block",
+	"padding-start":           "padding-inline-start",
+	"padding-end":             "padding-inline-end",
+	"padding-horizontal":      "padding-inline",
+	"padding-vertical":        "padding-block",
+	"border-start-width":      "border-inline-start-width",
+	"border-end-width":        "border-inline-end-width",
+	"border-horizontal-width": "border-inline-width",
+	"border-vertical-width":   "border-block-width",
+}
+
+// ExportHTML renders root and its descendants as a standalone HTML document.
+//
+// Every box carries data attributes with its node id (data-node-id, the id
+// set with SetID or the index path such as "0/2"), and its computed values
+// (data-left, data-top, data-width, data-height). Nodes with a measure func
+// have no content in the browser, so their flex mode size is only as good as
+// their explicit styles.
+func ExportHTML(root *Node, mode HTMLMode) string {
+	var str strings.Builder
+	str.WriteString("<!DOCTYPE html>\n<html>\n<head>\n  <meta charset=\"UTF-8\">\n  <title>Yoga layout</title>\n  <style>\n")
+	if mode == HTMLModeAbsolute {
+		str.WriteString(htmlAbsoluteCSS)
+	} else {
+		str.WriteString(htmlFlexCSS)
+	}
+	str.WriteString("  </style>\n</head>\n<body>\n")
+	if root != nil && root.node != nil {
+		var ref *Node
+		if mode == HTMLModeFlex {
+			ref = NewNodeWithConfig(root.GetConfig())
+			defer ref.Destroy()
+		}
+		exportHTMLNode(&str, root, nil, ref, mode, "", 1)
+	}
+	str.WriteString("</body>\n</html>\n")
+	return str.String()
+}
+
+func exportHTMLNode(str *strings.Builder, n, parent, ref *Node, mode HTMLMode, path string, level uint32) {
+	n.indent(str, level)
+	str.WriteString("<div")
+
+	id := n.GetID()
+	if id == "" {
+		id = path
+		if id == "" {
+			id = "root"
+		}
+	}
+	fmt.Fprintf(str, " data-node-id=\"%s\"", html.EscapeString(id))
+	if classes := n.GetClasses(); len(classes) > 0 {
+		fmt.Fprintf(str, " class=\"%s\"", html.EscapeString(strings.Join(classes, " ")))
+	}
+	layout := n.GetComputedLayout()
+	fmt.Fprintf(str, " data-left=\"%s\" data-top=\"%s\" data-width=\"%s\" data-height=\"%s\"",
+		formatFloat(layout.Left), formatFloat(layout.Top), formatFloat(layout.Width), formatFloat(layout.Height))
+	if n.HadOverflow() {
+		str.WriteString(" data-overflow=\"true\"")
+	}
+
+	var styles []string
+	if mode == HTMLModeAbsolute {
+		styles = absoluteStyles(n, parent, layout)
+	} else {
+		styles = flexStyles(n, ref, parent == nil)
+	}
+	if len(styles) > 0 {
+		fmt.Fprintf(str, " style=\"%s\"", html.EscapeString(strings.Join(styles, " ")))
+	}
+	str.WriteString(">")
+
+	childCount := n.GetChildCount()
+	if childCount > 0 {
+		str.WriteString("\n")
+		for i := uint32(0); i < childCount; i++ {
+			childPath := strconv.Itoa(int(i))
+			if path != "" {
+				childPath = path + "/" + childPath
+			}
+			exportHTMLNode(str, n.GetChild(i), n, ref, mode, childPath, level+1)
+		}
+		n.indent(str, level)
+	}
+	str.WriteString("</div>\n")
+}
+
+// flexStyles converts the non-default styles of n to CSS declarations
+func flexStyles(n, ref *Node, isRoot bool) []string {
+	var styles []string
+	for _, decl := range styleDeclarations(n, ref) {
+		if decl.Property == "flex" {
+			styles = append(styles, flexLonghands(n, n.GetFlex())...)
+			continue
+		}
+		property := decl.Property
+		if css, ok := cssProperties[property]; ok {
+			property = css
+		}
+		value := decl.Value
+		if isLengthProperty(decl.Property) {
+			value = cssLength(value)
+		}
+		styles = append(styles, fmt.Sprintf("%s: %s;", property, value))
+	}
+	if isRoot && n.GetDirection() == DirectionInherit {
+		styles = append(styles, fmt.Sprintf("direction: %s;", n.GetLayoutDirection()))
+	}
+	return styles
+}
+
+// flexLonghands expands flex the way Yoga resolves it: a positive flex is
+// the flex grow with a flex basis of 0 and a negative flex the flex shrink.
+// CSS flex: N would also let the item shrink. The flex-grow, flex-shrink
+// and flex-basis declarations of the node come later and override these.
+func flexLonghands(n *Node, flex float32) []string {
+	webDefaults := false
+	if config := n.GetConfig(); config != nil {
+		webDefaults = config.UseWebDefaults()
+	}
+	grow, shrink, basis := float32(0), float32(0), "auto"
+	if webDefaults {
+		shrink = 1
+	}
+	switch {
+	case flex > 0:
+		grow = flex
+		if !webDefaults {
+			basis = "0px"
+		}
+	case flex < 0 && !webDefaults:
+		shrink = -flex
+	}
+	return []string{
+		fmt.Sprintf("flex-grow: %s;", formatFloat(grow)),
+		fmt.Sprintf("flex-shrink: %s;", formatFloat(shrink)),
+		fmt.Sprintf("flex-basis: %s;", basis),
+	}
+}
+
+// isLengthProperty reports whether values of property are lengths
+func isLengthProperty(property string) bool {
+	switch property {
+	case "direction", "flex-direction", "justify-content", "align-content",
+		"align-items", "align-self", "position", "flex-wrap", "overflow",
+		"display", "box-sizing", "flex", "flex-grow", "flex-shrink", "aspect-ratio":
+		return false
+	}
+	return true
+}
+
+// cssLength appends the px unit to plain numbers
+func cssLength(value string) string {
+	if _, err := strconv.ParseFloat(value, 32); err == nil {
+		return value + "px"
+	}
+	return value
+}
+
+// absoluteStyles places n at its computed position inside parent's padding
+// box, which is what CSS absolute positioning is relative to
+func absoluteStyles(n, parent *Node, layout Layout) []string {
+	left, top := layout.Left, layout.Top
+	if parent != nil {
+		left -= parent.GetComputedBorder(EdgeLeft)
+		top -= parent.GetComputedBorder(EdgeTop)
+	}
+	styles := []string{
+		fmt.Sprintf("left: %spx;", formatFloat(left)),
+		fmt.Sprintf("top: %spx;", formatFloat(top)),
+		fmt.Sprintf("width: %spx;", formatFloat(layout.Width)),
+		fmt.Sprintf("height: %spx;", formatFloat(layout.Height)),
+	}
+	for _, edge := range []Edge{EdgeLeft, EdgeTop, EdgeRight, EdgeBottom} {
+		if border := n.GetComputedBorder(edge); border > 0 {
+			styles = append(styles, fmt.Sprintf("border-%s-width: %spx;", edge, formatFloat(border)))
+		}
+	}
+	if n.GetDisplay() == DisplayNone {
+		styles = append(styles, "display: none;")
+	}
+	return styles
+}
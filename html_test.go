@@ -0,0 +1,89 @@
+package yoga
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportHTML(t *testing.T) {
+	root := NewNode()
+	defer root.Destroy()
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(100)
+	root.SetHeight(50)
+	root.SetBorder(EdgeAll, 2)
+
+	child := NewNode()
+	defer child.Destroy()
+	child.SetFlexGrow(1)
+	child.SetMargin(EdgeStart, 4)
+	child.SetID("main")
+	root.InsertChild(child, 0)
+
+	other := NewNode()
+	defer other.Destroy()
+	other.SetWidthPercent(25)
+	root.InsertChild(other, 1)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	flex := ExportHTML(root, HTMLModeFlex)
+	for _, want := range []string{
+		`data-node-id="root"`,
+		`style="flex-direction: row; width: 100px; height: 50px; border-width: 2px; direction: ltr;"`,
+		`data-node-id="main" data-left="6" data-top="2" data-width="68" data-height="46" style="flex-grow: 1; margin-inline-start: 4px;"`,
+		`data-node-id="1" data-left="74" data-top="2" data-width="24" data-height="46" style="width: 25%;"`,
+	} {
+		if !strings.Contains(flex, want) {
+			t.Errorf("flex export missing %q:\n%s", want, flex)
+		}
+	}
+
+	absolute := ExportHTML(root, HTMLModeAbsolute)
+	for _, want := range []string{
+		`style="left: 0px; top: 0px; width: 100px; height: 50px; border-left-width: 2px; border-top-width: 2px; border-right-width: 2px; border-bottom-width: 2px;"`,
+		`data-node-id="main" data-left="6" data-top="2" data-width="68" data-height="46" style="left: 4px; top: 0px; width: 68px; height: 46px;"`,
+	} {
+		if !strings.Contains(absolute, want) {
+			t.Errorf("absolute export missing %q:\n%s", want, absolute)
+		}
+	}
+}
+
+func TestExportHTMLFlex(t *testing.T) {
+	root := NewNode()
+	defer root.Destroy()
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(100)
+
+	grow := NewNode()
+	defer grow.Destroy()
+	grow.SetFlex(2)
+	grow.SetWidth(300)
+	root.InsertChild(grow, 0)
+	shrink := NewNode()
+	defer shrink.Destroy()
+	shrink.SetFlex(-1)
+	root.InsertChild(shrink, 1)
+	basis := NewNode()
+	defer basis.Destroy()
+	basis.SetFlex(1)
+	basis.SetFlexShrink(1)
+	basis.SetFlexBasis(10)
+	root.InsertChild(basis, 2)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	// Yoga 的 flex 不会让项目收缩，CSS 的 flex: N 会
+	flex := ExportHTML(root, HTMLModeFlex)
+	for _, want := range []string{
+		`style="flex-grow: 2; flex-shrink: 0; flex-basis: 0px; width: 300px;"`,
+		`style="flex-grow: 0; flex-shrink: 1; flex-basis: auto;"`,
+		`style="flex-grow: 1; flex-shrink: 0; flex-basis: 0px; flex-shrink: 1; flex-basis: 10px;"`,
+	} {
+		if !strings.Contains(flex, want) {
+			t.Errorf("flex export missing %q:\n%s", want, flex)
+		}
+	}
+	if strings.Contains(flex, "flex: ") {
+		t.Errorf("flex export uses the flex shorthand:\n%s", flex)
+	}
+}
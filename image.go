@@ -0,0 +1,168 @@
+package yoga
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// BoxPaint is how RenderImage paints the border box of a node
+type BoxPaint struct {
+	Fill   color.Color
+	Stroke color.Color
+	// StrokeWidth is in pixels, 0 means no stroke
+	StrokeWidth int
+}
+
+// ImageOptions configures RenderImageWithOptions
+type ImageOptions struct {
+	// Background fills the whole image, nil means transparent
+	Background color.Color
+	// Paint returns the paint of a node, nil paints every node with a
+	// translucent fill by depth and a 1px stroke, red for nodes where
+	// HadOverflow is true
+	Paint func(n *Node) BoxPaint
+}
+
+var imageFills = []color.NRGBA{
+	{R: 0x1f, G: 0x5f, B: 0xbf, A: 0x30},
+	{R: 0x2e, G: 0x9e, B: 0x5b, A: 0x30},
+	{R: 0xe0, G: 0x8a, B: 0x1e, A: 0x30},
+	{R: 0x8e, G: 0x44, B: 0xad, A: 0x30},
+}
+
+var (
+	imageStroke   = color.NRGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+	imageOverflow = color.NRGBA{R: 0xdd, G: 0x33, B: 0x33, A: 0xff}
+)
+
+// RenderImage paints the border boxes of root and its descendants, scale
+// pixels per point. See RenderImageWithOptions.
+func RenderImage(root *Node, scale float32) *image.RGBA {
+	return RenderImageWithOptions(root, scale, nil)
+}
+
+// RenderImageWithOptions paints the border boxes of root and its
+// descendants in pre-order, so children are painted over their parents.
+//
+// Box edges are snapped to the pixel grid of the root's config like Yoga
+// rounds layouts: absolute positions are rounded to multiples of
+// 1/PointScaleFactor points before scaling, and a scale of 0 renders at the
+// PointScaleFactor. The image covers the root's border box.
+func RenderImageWithOptions(root *Node, scale float32, opts *ImageOptions) *image.RGBA {
+	var o ImageOptions
+	if opts != nil {
+		o = *opts
+	}
+	boxes := layoutBoxes(root)
+	if len(boxes) == 0 {
+		return image.NewRGBA(image.Rectangle{})
+	}
+
+	pointScale := float32(1)
+	if config := root.GetConfig(); config != nil && config.PointScaleFactor() > 0 {
+		pointScale = config.PointScaleFactor()
+	}
+	if scale <= 0 {
+		scale = pointScale
+	}
+	snap := func(v float32) int {
+		snapped := float32(math.Round(float64(v*pointScale))) / pointScale
+		return int(math.Round(float64(snapped * scale)))
+	}
+
+	origin := boxes[0]
+	rect := func(box *layoutBox) image.Rectangle {
+		x, y := box.x-origin.x, box.y-origin.y
+		return image.Rect(snap(x), snap(y), snap(x+box.layout.Width), snap(y+box.layout.Height))
+	}
+
+	img := image.NewRGBA(rect(origin))
+	if o.Background != nil {
+		draw.Draw(img, img.Bounds(), image.NewUniform(o.Background), image.Point{}, draw.Src)
+	}
+	for _, box := range boxes {
+		paint := defaultBoxPaint(box)
+		if o.Paint != nil {
+			paint = o.Paint(box.node)
+		}
+		r := rect(box)
+		if paint.Fill != nil {
+			draw.Draw(img, r, image.NewUniform(paint.Fill), image.Point{}, draw.Over)
+		}
+		if paint.Stroke != nil && paint.StrokeWidth > 0 {
+			strokeRect(img, r, paint.Stroke, paint.StrokeWidth)
+		}
+	}
+	return img
+}
+
+func defaultBoxPaint(box *layoutBox) BoxPaint {
+	paint := BoxPaint{Fill: imageFills[box.depth%len(imageFills)], Stroke: imageStroke, StrokeWidth: 1}
+	if box.node.HadOverflow() {
+		paint.Stroke = imageOverflow
+	}
+	return paint
+}
+
+// strokeRect draws a border of width pixels inside r
+func strokeRect(img draw.Image, r image.Rectangle, c color.Color, width int) {
+	src := image.NewUniform(c)
+	width = min(width, (r.Dx()+1)/2, (r.Dy()+1)/2)
+	if width <= 0 {
+		return
+	}
+	// 上、下整行，左、右去掉已画的角
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width), src, image.Point{}, draw.Over)
+	draw.Draw(img, image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y), src, image.Point{}, draw.Over)
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y+width, r.Min.X+width, r.Max.Y-width), src, image.Point{}, draw.Over)
+	draw.Draw(img, image.Rect(r.Max.X-width, r.Min.Y+width, r.Max.X, r.Max.Y-width), src, image.Point{}, draw.Over)
+}
+
+var (
+	diffChanged = color.RGBA{R: 0xff, A: 0xff}
+	diffMissing = color.RGBA{R: 0xff, G: 0x00, B: 0xff, A: 0xff}
+)
+
+// DiffImages compares two renders pixel by pixel and returns an image of
+// the union of their bounds together with the number of differing pixels.
+// Pixels whose channels all differ by at most tolerance are drawn as a faded
+// gray copy of a, differing pixels are red and pixels only covered by one
+// of the images are magenta.
+func DiffImages(a, b image.Image, tolerance uint8) (*image.RGBA, int) {
+	bounds := a.Bounds().Union(b.Bounds())
+	diff := image.NewRGBA(bounds)
+	changed := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+			inA, inB := p.In(a.Bounds()), p.In(b.Bounds())
+			if !inA || !inB {
+				diff.SetRGBA(x, y, diffMissing)
+				changed++
+				continue
+			}
+			ca := color.RGBAModel.Convert(a.At(x, y)).(color.RGBA)
+			cb := color.RGBAModel.Convert(b.At(x, y)).(color.RGBA)
+			if channelDiff(ca.R, cb.R) > tolerance || channelDiff(ca.G, cb.G) > tolerance ||
+				channelDiff(ca.B, cb.B) > tolerance || channelDiff(ca.A, cb.A) > tolerance {
+				diff.SetRGBA(x, y, diffChanged)
+				changed++
+				continue
+			}
+			// 预乘 alpha，相当于叠加在白色背景上
+			gray := color.GrayModel.Convert(ca).(color.Gray).Y + (0xff - ca.A)
+			faded := 0xff - (0xff-gray)/4
+			diff.SetRGBA(x, y, color.RGBA{R: faded, G: faded, B: faded, A: 0xff})
+		}
+	}
+	return diff, changed
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,101 @@
+package yoga
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func buildImageTree(config *Config) *Node {
+	root := NewNodeWithConfig(config)
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(10)
+	root.SetHeight(5)
+	for i := 0; i < 3; i++ {
+		child := newChildNode(config)
+		child.SetFlexGrow(1)
+		root.InsertChild(child, uint32(i))
+	}
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	return root
+}
+
+func TestRenderImage(t *testing.T) {
+	config := NewConfig()
+	root := buildImageTree(config)
+	defer root.FreeRecursive()
+
+	img := RenderImage(root, 2)
+	if img.Bounds() != image.Rect(0, 0, 20, 10) {
+		t.Fatalf("unexpected bounds %v", img.Bounds())
+	}
+	// the widths 3.33 are snapped to whole points: 3, 4, 3
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	img = RenderImageWithOptions(root, 2, &ImageOptions{
+		Background: color.White,
+		Paint: func(n *Node) BoxPaint {
+			if n.GetChildCount() > 0 {
+				return BoxPaint{}
+			}
+			return BoxPaint{Stroke: red, StrokeWidth: 1}
+		},
+	})
+	for x, want := range map[int]bool{5: true, 6: true, 7: false, 13: true, 14: true, 15: false} {
+		got := img.RGBAAt(x, 5) == color.RGBA{R: 0xff, A: 0xff}
+		if got != want {
+			t.Errorf("pixel %d: expected stroke %v, got %v", x, want, got)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, changed := DiffImages(img, decoded, 0); changed != 0 {
+		t.Errorf("expected PNG round trip to be lossless, %d pixels changed", changed)
+	}
+}
+
+func TestRenderImagePointScaleFactor(t *testing.T) {
+	config := NewConfig()
+	config.SetPointScaleFactor(2)
+	root := buildImageTree(config)
+	defer root.FreeRecursive()
+
+	// scale 0 renders at the point scale factor, on the half point grid
+	img := RenderImage(root, 0)
+	if img.Bounds() != image.Rect(0, 0, 20, 10) {
+		t.Fatalf("unexpected bounds %v", img.Bounds())
+	}
+}
+
+func TestDiffImages(t *testing.T) {
+	config := NewConfig()
+	root := buildImageTree(config)
+	defer root.FreeRecursive()
+	before := RenderImage(root, 1)
+
+	root.GetChild(0).SetFlexGrow(2)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	after := RenderImage(root, 1)
+
+	diff, changed := DiffImages(before, after, 0)
+	if changed == 0 {
+		t.Fatal("expected differences")
+	}
+	if diff.RGBAAt(0, 0) == diffChanged {
+		t.Error("unchanged corner pixel marked as changed")
+	}
+	if _, changed := DiffImages(before, before, 0); changed != 0 {
+		t.Errorf("expected no differences, got %d", changed)
+	}
+	if _, changed := DiffImages(before, image.NewRGBA(image.Rect(0, 0, 12, 5)), 255); changed != 10 {
+		t.Errorf("expected 10 pixels outside of the smaller image, got %d", changed)
+	}
+}
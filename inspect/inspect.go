@@ -0,0 +1,253 @@
+// Package inspect serves a live Yoga node tree to the browser.
+//
+// The page shows the tree, an overlay of the computed boxes and the style
+// and layout of the selected node. Styles edited in the page are applied
+// with Node.SetStyleProperty and the layout is calculated again.
+//
+//	go inspect.Serve("localhost:6061", root)
+package inspect
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/millken/yoga"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Option configures an Inspector
+type Option func(*Inspector)
+
+// WithLocker makes the inspector hold l while it reads or changes the tree,
+// for trees that are also used by other goroutines
+func WithLocker(l sync.Locker) Option {
+	return func(i *Inspector) {
+		i.mu = l
+	}
+}
+
+// WithViewport sets the arguments of CalculateLayout after an edit,
+// by default Undefined, Undefined and DirectionLTR
+func WithViewport(width, height float32, direction yoga.Direction) Option {
+	return func(i *Inspector) {
+		i.width, i.height, i.direction = width, height, direction
+	}
+}
+
+// Inspector is the http.Handler serving the inspector page and its API:
+//
+//	GET  /api/tree   the tree with styles and computed layouts as JSON
+//	POST /api/style  {"path": "0/1", "property": "width", "value": "50%"}
+//
+// Style requests must be sent as application/json from the same origin,
+// so that other pages open in the browser cannot change the tree.
+type Inspector struct {
+	root      *yoga.Node
+	mu        sync.Locker
+	width     float32
+	height    float32
+	direction yoga.Direction
+	mux       *http.ServeMux
+}
+
+// New creates an inspector for root
+func New(root *yoga.Node, opts ...Option) *Inspector {
+	i := &Inspector{
+		root:      root,
+		mu:        &sync.Mutex{},
+		width:     yoga.Undefined,
+		height:    yoga.Undefined,
+		direction: yoga.DirectionLTR,
+		mux:       http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	i.mux.HandleFunc("GET /{$}", i.serveIndex)
+	i.mux.HandleFunc("GET /api/tree", i.serveTree)
+	i.mux.HandleFunc("POST /api/style", i.serveStyle)
+	return i
+}
+
+// Serve listens on addr and serves the inspector for root until it fails
+func Serve(addr string, root *yoga.Node, opts ...Option) error {
+	return http.ListenAndServe(addr, New(root, opts...))
+}
+
+// ServeHTTP implements http.Handler
+func (i *Inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i.mux.ServeHTTP(w, r)
+}
+
+// Edges holds the computed left, top, right and bottom values of an edge property
+type Edges struct {
+	Left   float32 `json:"left"`
+	Top    float32 `json:"top"`
+	Right  float32 `json:"right"`
+	Bottom float32 `json:"bottom"`
+}
+
+// NodeInfo is the JSON description of a node served by /api/tree
+type NodeInfo struct {
+	Path      string             `json:"path"`
+	ID        string             `json:"id,omitempty"`
+	Classes   []string           `json:"classes,omitempty"`
+	Style     []yoga.Declaration `json:"style"`
+	Layout    yoga.Layout        `json:"layout"`
+	X         float32            `json:"x"`
+	Y         float32            `json:"y"`
+	Margin    Edges              `json:"margin"`
+	Border    Edges              `json:"border"`
+	Padding   Edges              `json:"padding"`
+	Direction string             `json:"direction"`
+	Overflow  bool               `json:"overflow,omitempty"`
+	Display   string             `json:"display"`
+	Children  []*NodeInfo        `json:"children,omitempty"`
+}
+
+// Tree describes the current state of the tree. The layout is calculated
+// with the viewport of the inspector when the root is dirty or has not been
+// laid out yet.
+func (i *Inspector) Tree() *NodeInfo {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.root != nil && (i.root.IsDirty() || yoga.IsNaN(i.root.GetComputedWidth())) {
+		// 没有布局时计算出的值是 NaN，无法编码成 JSON
+		i.root.CalculateLayout(i.width, i.height, i.direction)
+	}
+	s := yoga.NewTreeSnapshot(i.root, true)
+	if s == nil {
+		return nil
+	}
+	return describe(i.root, s.Root, "", 0, 0)
+}
+
+func describe(n *yoga.Node, s *yoga.NodeSnapshot, path string, parentX, parentY float32) *NodeInfo {
+	edges := func(get func(yoga.Edge) float32) Edges {
+		return Edges{Left: get(yoga.EdgeLeft), Top: get(yoga.EdgeTop), Right: get(yoga.EdgeRight), Bottom: get(yoga.EdgeBottom)}
+	}
+	info := &NodeInfo{
+		Path:      path,
+		ID:        s.ID,
+		Classes:   s.Classes,
+		Style:     s.Style,
+		Layout:    *s.Layout,
+		X:         parentX + s.Layout.Left,
+		Y:         parentY + s.Layout.Top,
+		Margin:    edges(n.GetComputedMargin),
+		Border:    edges(n.GetComputedBorder),
+		Padding:   edges(n.GetComputedPadding),
+		Direction: n.GetLayoutDirection().String(),
+		Overflow:  n.HadOverflow(),
+		Display:   n.GetDisplay().String(),
+	}
+	if info.Style == nil {
+		info.Style = []yoga.Declaration{}
+	}
+	for idx, child := range s.Children {
+		childPath := strconv.Itoa(idx)
+		if path != "" {
+			childPath = path + "/" + childPath
+		}
+		info.Children = append(info.Children, describe(n.GetChild(uint32(idx)), child, childPath, info.X, info.Y))
+	}
+	return info
+}
+
+// SetStyle sets a style property of the node at path, an index path such
+// as "0/2" with "" for the root, and calculates the layout again
+func (i *Inspector) SetStyle(path, property, value string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	n, err := nodeAt(i.root, path)
+	if err != nil {
+		return err
+	}
+	if err := n.SetStyleProperty(property, value); err != nil {
+		return err
+	}
+	i.root.CalculateLayout(i.width, i.height, i.direction)
+	return nil
+}
+
+func nodeAt(root *yoga.Node, path string) (*yoga.Node, error) {
+	n := root
+	if path == "" {
+		return n, nil
+	}
+	for _, part := range strings.Split(path, "/") {
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= n.GetChildCount() {
+			return nil, fmt.Errorf("invalid node path: %s", path)
+		}
+		n = n.GetChild(uint32(index))
+	}
+	return n, nil
+}
+
+func (i *Inspector) serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(indexHTML)
+}
+
+func (i *Inspector) serveTree(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, i.Tree())
+}
+
+func (i *Inspector) serveStyle(w http.ResponseWriter, r *http.Request) {
+	// 表单可以跨域提交 text/plain，所以要求 JSON 并拒绝其他来源的请求
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request", http.StatusForbidden)
+		return
+	}
+	var req struct {
+		Path     string `json:"path"`
+		Property string `json:"property"`
+		Value    string `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := i.SetStyle(req.Path, req.Property, req.Value); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, i.Tree())
+}
+
+// sameOrigin reports whether r was not sent by a page of another origin,
+// using the Sec-Fetch-Site and Origin headers of browsers
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "", "same-origin", "none":
+	default:
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,110 @@
+package inspect
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+	"github.com/millken/yoga"
+)
+
+func TestInspector(t *testing.T) {
+	r := assert.New(t)
+	config := yoga.NewConfig()
+	root := yoga.NewNodeWithConfig(config)
+	root.SetFlexDirection(yoga.FlexDirectionRow)
+	root.SetWidth(100)
+	root.SetHeight(50)
+	root.SetPadding(yoga.EdgeAll, 5)
+	child := yoga.NewNodeWithConfig(config)
+	child.SetID("panel")
+	child.SetWidth(20)
+	root.InsertChild(child, 0)
+	defer func() {
+		child.Destroy()
+		root.Destroy()
+	}()
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+
+	server := httptest.NewServer(New(root))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	r.Equal(nil, err)
+	r.Equal(http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	var tree NodeInfo
+	resp, err = http.Get(server.URL + "/api/tree")
+	r.Equal(nil, err)
+	r.Equal(nil, json.NewDecoder(resp.Body).Decode(&tree))
+	resp.Body.Close()
+	r.Equal(1, len(tree.Children))
+	r.Equal("0", tree.Children[0].Path)
+	r.Equal("panel", tree.Children[0].ID)
+	r.Equal(float32(5), tree.Children[0].X)
+	r.Equal(Edges{Left: 5, Top: 5, Right: 5, Bottom: 5}, tree.Padding)
+
+	resp, err = http.Post(server.URL+"/api/style", "application/json",
+		strings.NewReader(`{"path": "0", "property": "flex-grow", "value": "1"}`))
+	r.Equal(nil, err)
+	r.Equal(http.StatusOK, resp.StatusCode)
+	r.Equal(nil, json.NewDecoder(resp.Body).Decode(&tree))
+	resp.Body.Close()
+	r.Equal(float32(90), tree.Children[0].Layout.Width)
+	r.Equal(float32(90), child.GetComputedWidth())
+	r.Equal([]yoga.Declaration{{Property: "flex-grow", Value: "1"}, {Property: "width", Value: "20"}}, tree.Children[0].Style)
+
+	post := func(contentType string, header http.Header) int {
+		req, err := http.NewRequest("POST", server.URL+"/api/style",
+			strings.NewReader(`{"path": "0", "property": "flex-grow", "value": "2"}`))
+		r.Equal(nil, err)
+		req.Header = header
+		req.Header.Set("Content-Type", contentType)
+		resp, err := http.DefaultClient.Do(req)
+		r.Equal(nil, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	r.Equal(http.StatusUnsupportedMediaType, post("text/plain", http.Header{}))
+	r.Equal(http.StatusForbidden, post("application/json", http.Header{"Origin": {"http://example.com"}}))
+	r.Equal(http.StatusForbidden, post("application/json", http.Header{"Sec-Fetch-Site": {"cross-site"}}))
+	r.Equal(float32(1), child.GetFlexGrow())
+	r.Equal(http.StatusOK, post("application/json; charset=utf-8", http.Header{"Origin": {server.URL}, "Sec-Fetch-Site": {"same-origin"}}))
+	r.Equal(float32(2), child.GetFlexGrow())
+
+	for _, body := range []string{
+		`{"path": "3", "property": "width", "value": "1"}`,
+		`{"path": "0", "property": "colour", "value": "red"}`,
+		`{"path": "0", "property": "width", "value": "wide"}`,
+		`not json`,
+	} {
+		resp, err = http.Post(server.URL+"/api/style", "application/json", strings.NewReader(body))
+		r.Equal(nil, err)
+		r.Equal(http.StatusBadRequest, resp.StatusCode)
+		resp.Body.Close()
+	}
+}
+
+func TestInspectorTreeBeforeLayout(t *testing.T) {
+	r := assert.New(t)
+	root := yoga.NewNode()
+	defer root.Destroy()
+	root.SetFlexDirection(yoga.FlexDirectionRow)
+
+	server := httptest.NewServer(New(root, WithViewport(200, 100, yoga.DirectionLTR)))
+	defer server.Close()
+
+	// 宿主还没有计算布局
+	var tree NodeInfo
+	resp, err := http.Get(server.URL + "/api/tree")
+	r.Equal(nil, err)
+	r.Equal(http.StatusOK, resp.StatusCode)
+	r.Equal(nil, json.NewDecoder(resp.Body).Decode(&tree))
+	resp.Body.Close()
+	r.Equal(float32(200), tree.Layout.Width)
+	r.Equal(float32(100), tree.Layout.Height)
+}
@@ -7,6 +7,7 @@ import "C"
 import (
 	"fmt"
 	"runtime"
+	"slices"
 	"strings"
 )
 
@@ -33,8 +34,7 @@ type MeasureFunc func(width float32, widthMode MeasureMode, height float32, heig
 type DirtiedFunc func()
 
 type Node struct {
-	node    C.YGNodeRef
-	context interface{}
+	node C.YGNodeRef
 }
 
 // NewNode creates a default node
@@ -44,17 +44,26 @@ func NewNode() *Node {
 	return n
 }
 
-// NewNodeWithConfig creates a node with the specified configuration
+// NewNodeWithConfig creates a node with the specified configuration, the
+// node keeps the config alive until it is destroyed
 func NewNodeWithConfig(config *Config) *Node {
 	node := C.YGNodeNewWithConfig(config.ref())
 	if node == nil {
 		return nil
 	}
 	n := &Node{node: node}
+	// 节点不持有 config 时，config 的 finalizer 可能在节点使用它时释放它
+	getNodeAttrs(node, true).config = config
 	runtime.SetFinalizer(n, (*Node).Destroy)
 	return n
 }
 
+// newChildNode creates a node without a finalizer, for nodes whose lifetime
+// is managed by the tree they are inserted into (see FreeRecursive)
+func newChildNode(config *Config) *Node {
+	return wrapNodeRef(C.YGNodeNewWithConfig(config.ref()))
+}
+
 // Clone creates a copy of the node with the same context and children, but no owner set
 func (n *Node) Clone() *Node {
 	if n.node == nil {
@@ -64,18 +73,20 @@ func (n *Node) Clone() *Node {
 	if clonedNode == nil {
 		return nil
 	}
+	cloneNodeContext(n.node, clonedNode)
 	newNode := &Node{
-		node:    clonedNode,
-		context: n.context, // 直接复制 context
+		node: clonedNode,
 	}
 	runtime.SetFinalizer(newNode, (*Node).Destroy)
 	return newNode
 }
 
-// SetConfig sets the configuration for the node
+// SetConfig sets the configuration for the node, the node keeps the config
+// alive until it is destroyed
 func (n *Node) SetConfig(config *Config) {
 	if n.node != nil && config != nil {
 		C.YGNodeSetConfig(n.node, config.ref())
+		getNodeAttrs(n.node, true).config = config
 	}
 }
 
@@ -91,20 +102,97 @@ func (n *Node) GetConfig() *Config {
 	return nil
 }
 
-// SetContext sets the context for the node
+// SetContext sets the context for the node, it is kept with the node and
+// can be read from any wrapper of it, such as those returned by GetChild
 func (n *Node) SetContext(context interface{}) {
 	if n.node == nil {
 		return
 	}
-	n.context = context
+	// 没有 context 时不必创建 nodeAttrs
+	attrs := getNodeAttrs(n.node, context != nil)
+	if attrs != nil {
+		attrs.context = context
+	}
 }
 
 // GetContext gets the context of the node
 func (n *Node) GetContext() interface{} {
-	if n.node == nil {
+	attrs := getNodeAttrs(n.node, false)
+	if attrs == nil {
 		return nil
 	}
-	return n.context
+	return attrs.context
+}
+
+// SetID sets the identifier of the node used by selectors
+func (n *Node) SetID(id string) {
+	if n.node == nil {
+		return
+	}
+	getNodeAttrs(n.node, true).id = id
+}
+
+// GetID gets the identifier of the node
+func (n *Node) GetID() string {
+	if attrs := getNodeAttrs(n.node, false); attrs != nil {
+		return attrs.id
+	}
+	return ""
+}
+
+// SetClasses replaces the class names of the node
+func (n *Node) SetClasses(classes ...string) {
+	if n.node == nil {
+		return
+	}
+	attrs := getNodeAttrs(n.node, true)
+	attrs.classes = attrs.classes[:0]
+	for _, class := range classes {
+		if class != "" && !slices.Contains(attrs.classes, class) {
+			attrs.classes = append(attrs.classes, class)
+		}
+	}
+}
+
+// GetClasses gets the class names of the node
+func (n *Node) GetClasses() []string {
+	if attrs := getNodeAttrs(n.node, false); attrs != nil && len(attrs.classes) > 0 {
+		return append([]string(nil), attrs.classes...)
+	}
+	return nil
+}
+
+// AddClass adds a class name to the node
+func (n *Node) AddClass(class string) {
+	if n.node == nil || class == "" {
+		return
+	}
+	attrs := getNodeAttrs(n.node, true)
+	if !slices.Contains(attrs.classes, class) {
+		attrs.classes = append(attrs.classes, class)
+	}
+}
+
+// RemoveClass removes a class name from the node
+func (n *Node) RemoveClass(class string) {
+	attrs := getNodeAttrs(n.node, false)
+	if attrs == nil {
+		return
+	}
+	for i, c := range attrs.classes {
+		if c == class {
+			attrs.classes = append(attrs.classes[:i], attrs.classes[i+1:]...)
+			return
+		}
+	}
+}
+
+// HasClass checks if the node has the given class name
+func (n *Node) HasClass(class string) bool {
+	if attrs := getNodeAttrs(n.node, false); attrs != nil {
+		return slices.Contains(attrs.classes, class)
+	}
+	return false
 }
 
 // SetNodeType Sets whether a leaf node's layout results may be truncated during layout rounding.
@@ -379,6 +467,10 @@ func (n *Node) GetRawHeight() float32 {
 // BaselineFunc defines the type for the baseline callback function
 type BaselineFunc func(width float32, height float32) float32
 
+// NodeBaselineFunc is a baseline callback receiving the node it runs for,
+// so that it doesn't have to capture the node and keep it reachable
+type NodeBaselineFunc func(n *Node, width float32, height float32) float32
+
 // SetMeasureFunc sets the measurement function
 func (n *Node) SetMeasureFunc(measureFunc MeasureFunc) {
 	if n.node == nil {
@@ -414,10 +506,54 @@ func (n *Node) GetMeasureFunc() MeasureFunc {
 	return nil
 }
 
-// SetBaselineFunc sets the baseline function
+// SetBaselineFunc sets the baseline function used by AlignBaseline, nil unsets it
 func (n *Node) SetBaselineFunc(baselineFunc BaselineFunc) {
-	// This method requires additional cgo callback implementation
-	// Similar to SetMeasureFunc
+	if n.node == nil {
+		return
+	}
+	if baselineFunc == nil {
+		setBaselineHandle(n.node, nil)
+		C.YGNodeSetBaselineFunc(n.node, nil)
+		return
+	}
+	setBaselineHandle(n.node, baselineFunc)
+	C.YGNodeSetBaselineFunc(n.node, (C.YGBaselineFunc)(C.goBaselineInvoke))
+}
+
+// SetNodeBaselineFunc sets a baseline function receiving the node like
+// SetBaselineFunc, nil unsets it
+func (n *Node) SetNodeBaselineFunc(baselineFunc NodeBaselineFunc) {
+	if baselineFunc == nil {
+		n.SetBaselineFunc(nil)
+		return
+	}
+	if n.node == nil {
+		return
+	}
+	setBaselineHandle(n.node, baselineFunc)
+	C.YGNodeSetBaselineFunc(n.node, (C.YGBaselineFunc)(C.goBaselineInvoke))
+}
+
+// GetBaselineFunc gets the current baseline function
+func (n *Node) GetBaselineFunc() BaselineFunc {
+	if n.node == nil {
+		return nil
+	}
+	contextPtr := C.YGNodeGetContext(n.node)
+	if contextPtr == nil {
+		return nil
+	}
+	if h := (*NodeContext)(contextPtr).baselineHandle; h != 0 {
+		switch baselineFunc := h.Value().(type) {
+		case BaselineFunc:
+			return baselineFunc
+		case NodeBaselineFunc:
+			return func(width, height float32) float32 {
+				return baselineFunc(n, width, height)
+			}
+		}
+	}
+	return nil
 }
 
 // HasBaselineFunc checks if a baseline function is set
@@ -1138,16 +1274,22 @@ func (n *Node) SetMaxHeightStretch() {
 }
 
 // SwapChild replaces the child node at the specified index with a new one
+// and marks the node dirty
 func (n *Node) SwapChild(child *Node, index uint32) {
 	if n.node != nil && child.node != nil {
 		C.YGNodeSwapChild(n.node, child.node, C.size_t(index))
+		// YGNodeSwapChild 不会标记 dirty，需要手动传播
+		C.yg_node_mark_dirty_and_propagate(n.node)
 	}
 }
 
 // FreeRecursive frees the node and all its children recursively
 func (n *Node) FreeRecursive() {
 	if n.node != nil {
+		// 清理子树中所有节点的上下文（包含所有回调句柄）
+		deleteNodeContextRecursive(n.node)
 		C.YGNodeFreeRecursive(n.node)
+		n.node = nil
 	}
 }
 
@@ -181,6 +181,72 @@ func TestNodeMeasureFuncReplaceAndUnset(t *testing.T) {
 	}
 }
 
+func TestNodeBaselineFunc(t *testing.T) {
+	root := NewNode()
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetAlignItems(AlignBaseline)
+	first := NewNode()
+	first.SetWidth(10)
+	first.SetHeight(40)
+	second := NewNode()
+	second.SetWidth(10)
+	second.SetHeight(20)
+	root.InsertChild(first, 0)
+	root.InsertChild(second, 1)
+	defer func() {
+		second.Destroy()
+		first.Destroy()
+		root.Destroy()
+	}()
+
+	first.SetBaselineFunc(func(width, height float32) float32 {
+		return height / 2
+	})
+	if !first.HasBaselineFunc() || first.GetBaselineFunc() == nil {
+		t.Fatal("expected baseline func to be set")
+	}
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	// second 的基线是底边 20，对齐到 first 的基线 20
+	if got := second.GetComputedTop(); got != 0 {
+		t.Fatalf("expected second top 0, got %v", got)
+	}
+
+	first.SetBaselineFunc(func(width, height float32) float32 {
+		return 35
+	})
+	// 更换 baseline 函数不会标记 dirty，改变高度触发重新布局
+	first.SetHeight(41)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	if got := second.GetComputedTop(); got != 15 {
+		t.Fatalf("expected second top 15, got %v", got)
+	}
+
+	first.SetBaselineFunc(nil)
+	if first.HasBaselineFunc() || first.GetBaselineFunc() != nil {
+		t.Fatal("expected baseline func to be unset")
+	}
+
+	// NodeBaselineFunc 收到它所属的节点
+	first.SetPadding(EdgeTop, 5)
+	first.SetNodeBaselineFunc(func(n *Node, width, height float32) float32 {
+		return n.GetComputedPadding(EdgeTop) + 10
+	})
+	if !first.HasBaselineFunc() || first.GetBaselineFunc() == nil {
+		t.Fatal("expected node baseline func to be set")
+	}
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	if got := second.GetComputedTop(); got != 0 {
+		t.Fatalf("expected second top 0, got %v", got)
+	}
+	if got := root.GetChild(0).GetBaselineFunc()(10, 41); got != 15 {
+		t.Fatalf("expected baseline 15, got %v", got)
+	}
+	first.SetNodeBaselineFunc(nil)
+	if first.HasBaselineFunc() || first.GetBaselineFunc() != nil {
+		t.Fatal("expected node baseline func to be unset")
+	}
+}
+
 func TestNodeContext(t *testing.T) {
 	node := NewNode()
 	defer node.Destroy()
@@ -303,6 +369,31 @@ func TestNodeContext(t *testing.T) {
 	}
 }
 
+func TestNodeContextChild(t *testing.T) {
+	root := NewNode()
+	defer root.Destroy()
+	child := NewNode()
+	defer child.Destroy()
+	root.InsertChild(child, 0)
+
+	// GetChild 每次返回新的包装，context 需要保存在节点上
+	child.SetContext("child")
+	if got := root.GetChild(0).GetContext(); got != "child" {
+		t.Errorf("Expected context 'child' from GetChild, got %v", got)
+	}
+	root.GetChild(0).SetContext(42)
+	if got := child.GetContext(); got != 42 {
+		t.Errorf("Expected context 42 set through GetChild, got %v", got)
+	}
+	if got := root.GetChild(0).GetParent().GetContext(); got != nil {
+		t.Errorf("Expected nil context on parent, got %v", got)
+	}
+	child.SetContext(nil)
+	if got := root.GetChild(0).GetContext(); got != nil {
+		t.Errorf("Expected nil context after setting nil, got %v", got)
+	}
+}
+
 func TestNodeToYogaLayout(t *testing.T) {
 	// Test classic mobile layout: Header, Content, Footer
 	root := NewNode()
@@ -0,0 +1,302 @@
+package yoga
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// breakHint is a set of pagination hints of a node
+type breakHint uint8
+
+const (
+	hintAvoidBreakInside breakHint = 1 << iota
+	hintKeepWithNext
+	hintRepeatHeader
+)
+
+func (n *Node) setBreakHint(hint breakHint, on bool) {
+	if n.node == nil {
+		return
+	}
+	attrs := getNodeAttrs(n.node, true)
+	if on {
+		attrs.breakHints |= hint
+	} else {
+		attrs.breakHints &^= hint
+	}
+}
+
+func (n *Node) getBreakHints() breakHint {
+	if attrs := getNodeAttrs(n.node, false); attrs != nil {
+		return attrs.breakHints
+	}
+	return 0
+}
+
+// SetAvoidBreakInside asks Paginate not to break the node across pages,
+// like CSS break-inside: avoid. Nodes taller than a page are still broken.
+func (n *Node) SetAvoidBreakInside(avoid bool) {
+	n.setBreakHint(hintAvoidBreakInside, avoid)
+}
+
+// GetAvoidBreakInside gets whether Paginate avoids breaking the node
+func (n *Node) GetAvoidBreakInside() bool {
+	return n.getBreakHints()&hintAvoidBreakInside != 0
+}
+
+// SetKeepWithNext asks Paginate not to break between the node and its next
+// sibling, e.g. to keep a heading with the first paragraph after it
+func (n *Node) SetKeepWithNext(keep bool) {
+	n.setBreakHint(hintKeepWithNext, keep)
+}
+
+// GetKeepWithNext gets whether Paginate keeps the node with its next sibling
+func (n *Node) GetKeepWithNext() bool {
+	return n.getBreakHints()&hintKeepWithNext != 0
+}
+
+// SetRepeatHeader marks the node as the header of its parent, such as the
+// header row of a table: Paginate repeats it at the top of every page the
+// parent continues on, and keeps it with its next sibling
+func (n *Node) SetRepeatHeader(repeat bool) {
+	n.setBreakHint(hintRepeatHeader, repeat)
+}
+
+// GetRepeatHeader gets whether the node is repeated as the header of its parent
+func (n *Node) GetRepeatHeader() bool {
+	return n.getBreakHints()&hintRepeatHeader != 0
+}
+
+// PaginateOptions configures Paginate
+type PaginateOptions struct {
+	// Header is a running header drawn at the top of every page. It is laid
+	// out on its own, not as part of root, and its computed height is
+	// reserved on every page.
+	Header *Node
+}
+
+// Page is a slice of the computed layout of root
+type Page struct {
+	// Top and Bottom delimit the part of the layout shown on the page, in
+	// the coordinates of the root's parent
+	Top, Bottom float32
+	// Items are the nodes on the page in paint order: the running header,
+	// the repeated headers and the nodes overlapping Top to Bottom
+	Items []PageItem
+}
+
+// PageItem is a node placed on a page
+type PageItem struct {
+	Node *Node
+	// Path is the index path of the node from root such as "0/2", or from
+	// the running header for its nodes
+	Path string
+	// X and Y are the position of the border box on the page
+	X, Y float32
+	// Repeated is set for the nodes of the running header and of repeated
+	// headers
+	Repeated bool
+	// Continued is set when the node started on an earlier page and
+	// Continues when it goes on on a later page
+	Continued, Continues bool
+}
+
+// pageNode is a box of the layout with its in-flow children sorted by top
+type pageNode struct {
+	box   *layoutBox
+	hints breakHint
+	flow  []*pageNode
+	// headers 是标记为 repeat header 的子节点
+	headers []*pageNode
+}
+
+func (p *pageNode) top() float32    { return p.box.y }
+func (p *pageNode) bottom() float32 { return p.box.y + p.box.layout.Height }
+
+// clear reports whether no child of p other than except straddles y
+func (p *pageNode) clear(y float32, except *pageNode) bool {
+	for _, c := range p.flow {
+		if c != except && c.top() < y && c.bottom() > y {
+			return false
+		}
+	}
+	return true
+}
+
+// findBreak returns the lowest y in (top, limit] where the content of p
+// can be broken: before a child that is not the first, or inside a child
+// crossing limit. Unless strict is false, breaks after nodes kept with
+// their next sibling and inside nodes avoiding breaks are not allowed.
+func (p *pageNode) findBreak(top, limit float32, strict bool) (float32, bool) {
+	var best float32
+	found := false
+	for i, c := range p.flow {
+		if c.top() > limit {
+			break
+		}
+		if i > 0 && c.top() > top && c.top() > best && p.clear(c.top(), nil) {
+			keep := p.flow[i-1].hints&(hintKeepWithNext|hintRepeatHeader) != 0
+			if !strict || !keep {
+				best, found = c.top(), true
+			}
+		}
+		if c.top() < limit && c.bottom() > limit && (!strict || c.hints&hintAvoidBreakInside == 0) {
+			if y, ok := c.findBreak(top, limit, strict); ok && y > best && p.clear(y, c) {
+				best, found = y, true
+			}
+		}
+	}
+	return best, found
+}
+
+// Paginate splits the computed layout of root into pages of pageHeight.
+//
+// Pages are broken between the children of a node, before a child that
+// starts below the top of the page, choosing the lowest break that fits.
+// When a child crosses the bottom of the page Paginate also looks for
+// breaks inside it, recursively, so tall containers flow over several
+// pages; a break is only used when no sibling straddles it, so the
+// children of rows are not cut. The hints set with SetAvoidBreakInside,
+// SetKeepWithNext and SetRepeatHeader are honoured unless no page break
+// is possible otherwise. As a last resort the page is cut at pageHeight.
+//
+// Nodes with display none are skipped, absolutely positioned nodes are
+// placed with their layout but never cause a break.
+func Paginate(root *Node, pageHeight float32, opts *PaginateOptions) ([]Page, error) {
+	var o PaginateOptions
+	if opts != nil {
+		o = *opts
+	}
+	if !(pageHeight > 0) {
+		return nil, fmt.Errorf("invalid page height %v", pageHeight)
+	}
+	boxes := layoutBoxes(root)
+	if len(boxes) == 0 {
+		return nil, nil
+	}
+
+	nodes := make(map[*layoutBox]*pageNode, len(boxes))
+	end := boxes[0].y
+	for _, box := range boxes {
+		p := &pageNode{box: box, hints: box.node.getBreakHints()}
+		nodes[box] = p
+		end = max(end, p.bottom())
+		if box.parent == nil {
+			continue
+		}
+		parent := nodes[box.parent]
+		if box.node.GetPositionType() != PositionTypeAbsolute {
+			parent.flow = append(parent.flow, p)
+		}
+		if p.hints&hintRepeatHeader != 0 {
+			parent.headers = append(parent.headers, p)
+		}
+	}
+	for _, p := range nodes {
+		sort.SliceStable(p.flow, func(i, j int) bool { return p.flow[i].top() < p.flow[j].top() })
+	}
+	rootNode := nodes[boxes[0]]
+
+	var header []*layoutBox
+	var headerHeight float32
+	if o.Header != nil {
+		header = layoutBoxes(o.Header)
+		headerHeight = o.Header.GetComputedHeight()
+	}
+
+	var pages []Page
+	for top := rootNode.top(); len(pages) == 0 || top < end; {
+		page := Page{Top: top}
+		offset := headerHeight
+		for _, box := range header {
+			page.Items = append(page.Items, PageItem{
+				Node: box.node, Path: box.path, X: box.x, Y: box.y - header[0].y, Repeated: true,
+			})
+		}
+		// 重复被分页的祖先节点中已经出现过的表头
+		var repeat func(p *pageNode)
+		repeat = func(p *pageNode) {
+			if !(p.top() < top && p.bottom() > top) {
+				return
+			}
+			for _, h := range p.headers {
+				if h.bottom() > top {
+					continue
+				}
+				for _, box := range boxes {
+					if box == h.box || isDescendantBox(box, h.box) {
+						page.Items = append(page.Items, PageItem{
+							Node: box.node, Path: box.path, X: box.x, Y: box.y - h.top() + offset, Repeated: true,
+						})
+					}
+				}
+				offset += h.box.layout.Height
+			}
+			for _, c := range p.flow {
+				repeat(c)
+			}
+		}
+		repeat(rootNode)
+
+		if offset >= pageHeight {
+			return nil, fmt.Errorf("headers of page %d are %v high, taller than the page", len(pages)+1, offset)
+		}
+		limit := top + pageHeight - offset
+		page.Bottom = min(limit, end)
+		if limit < end {
+			y, ok := rootNode.findBreak(top, limit, true)
+			if !ok {
+				y, ok = rootNode.findBreak(top, limit, false)
+			}
+			if ok {
+				page.Bottom = y
+			}
+		}
+
+		for _, box := range boxes {
+			bottom := box.y + box.layout.Height
+			if box.y < page.Bottom && bottom > top || box.y >= top && box.y < page.Bottom || box.parent == nil {
+				page.Items = append(page.Items, PageItem{
+					Node:      box.node,
+					Path:      box.path,
+					X:         box.x,
+					Y:         box.y - top + offset,
+					Continued: box.y < top,
+					Continues: bottom > page.Bottom,
+				})
+			}
+		}
+		pages = append(pages, page)
+		top = page.Bottom
+	}
+	return pages, nil
+}
+
+// isDescendantBox reports whether box is in the subtree below ancestor
+func isDescendantBox(box, ancestor *layoutBox) bool {
+	for p := box.parent; p != nil; p = p.parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists the paths of the items on the page for debugging, "." is
+// root and repeated items are marked with "*"
+func (p Page) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "page %v-%v:", p.Top, p.Bottom)
+	for _, item := range p.Items {
+		path := item.Path
+		if path == "" {
+			path = "."
+		}
+		if item.Repeated {
+			path += "*"
+		}
+		b.WriteString(" " + path)
+	}
+	return b.String()
+}
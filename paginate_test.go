@@ -0,0 +1,84 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func pageStrings(pages []Page) []string {
+	var s []string
+	for _, page := range pages {
+		s = append(s, page.String())
+	}
+	return s
+}
+
+func TestPaginate(t *testing.T) {
+	r := assert.New(t)
+	table := []Option{ID("table"), Box(ID("th"), Height(10), RepeatHeader())}
+	for i := 0; i < 8; i++ {
+		table = append(table, Box(Height(20)))
+	}
+	root, nodes := Build(nil, Column(Width(100),
+		Box(ID("title"), Height(30), KeepWithNext()),
+		Box(Height(40)),
+		Column(table...),
+		Column(ID("footer"), AvoidBreakInside(), Box(Height(25)), Box(Height(25))),
+	))
+	defer root.FreeRecursive()
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	r.True(nodes["title"].GetKeepWithNext())
+	r.True(nodes["footer"].GetAvoidBreakInside())
+	r.False(nodes["footer"].GetRepeatHeader())
+
+	pages, err := Paginate(root, 100, nil)
+	r.Equal(nil, err)
+	r.Equal([]string{
+		"page 0-100: . 0 1 2 2/0 2/1",
+		"page 100-180: 2/0* . 2 2/2 2/3 2/4 2/5",
+		"page 180-240: 2/0* . 2 2/6 2/7 2/8",
+		"page 240-290: . 3 3/0 3/1",
+	}, pageStrings(pages))
+
+	second := pages[1]
+	r.Equal(PageItem{Node: second.Items[0].Node, Path: "2/0", X: 0, Y: 0, Repeated: true}, second.Items[0])
+	r.True(second.Items[1].Continued)
+	r.True(second.Items[1].Continues)
+	r.Equal("2/2", second.Items[3].Path)
+	r.Equal(float32(10), second.Items[3].Y)
+	r.False(second.Items[3].Continues)
+
+	_, err = Paginate(root, 0, nil)
+	r.NotNil(err)
+}
+
+func TestPaginateForcedBreaks(t *testing.T) {
+	r := assert.New(t)
+	root, _ := Build(nil, Column(Width(100),
+		Box(Height(50)),
+		Column(AvoidBreakInside(), Box(Height(50)), Box(Height(50)), Box(Height(50))),
+		Row(Box(Width(50), Height(30)), Column(Flex(1), Box(Height(15)), Box(Height(15)))),
+	))
+	defer root.FreeRecursive()
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	header, _ := Build(nil, Box(Width(100), Height(20)))
+	defer header.FreeRecursive()
+	header.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	pages, err := Paginate(root, 100, &PaginateOptions{Header: header})
+	r.Equal(nil, err)
+	// 第二个子节点比页面高，只能在其内部断开；行内的子节点不会被切开
+	r.Equal([]string{
+		"page 0-50: .* . 0",
+		"page 50-100: .* . 1 1/0",
+		"page 100-150: .* . 1 1/1",
+		"page 150-230: .* . 1 1/2 2 2/0 2/1 2/1/0 2/1/1",
+	}, pageStrings(pages))
+	r.Equal(float32(20), pages[1].Items[3].Y)
+
+	_, err = Paginate(root, 20, &PaginateOptions{Header: header})
+	r.NotNil(err)
+}
@@ -0,0 +1,97 @@
+package yoga
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintOptions selects what DebugString prints, mirroring YGPrintOptions
+type PrintOptions uint32
+
+const (
+	// PrintOptionsLayout prints the computed layout
+	PrintOptionsLayout PrintOptions = 1 << iota
+	// PrintOptionsStyle prints the style properties that differ from a fresh node
+	PrintOptionsStyle
+	// PrintOptionsChildren prints the children recursively
+	PrintOptionsChildren
+)
+
+// DebugString returns an HTML-like description of the node in the format of
+// Yoga's NodeToString, e.g.
+//
+//	<div layout="width: 100; height: 100; top: 0; left: 0;" style="flex-direction: row; width: 100;" >
+//	  <div layout="width: 50; height: 100; top: 0; left: 0;" style="flex-grow: 1;" ></div>
+//	</div>
+func (n *Node) DebugString(opts PrintOptions) string {
+	if n == nil || n.node == nil {
+		return "<nil>"
+	}
+	var str strings.Builder
+	var ref *Node
+	if opts&PrintOptionsStyle != 0 {
+		ref = NewNodeWithConfig(n.GetConfig())
+		defer ref.Destroy()
+	}
+	n.printNode(&str, ref, opts, 0)
+	return str.String()
+}
+
+func (n *Node) printNode(str *strings.Builder, ref *Node, opts PrintOptions, level uint32) {
+	n.indent(str, level)
+	str.WriteString("<div")
+	if id := n.GetID(); id != "" {
+		fmt.Fprintf(str, " id=\"%s\"", id)
+	}
+	if classes := n.GetClasses(); len(classes) > 0 {
+		fmt.Fprintf(str, " class=\"%s\"", strings.Join(classes, " "))
+	}
+	if opts&PrintOptionsLayout != 0 {
+		fmt.Fprintf(str, " layout=\"width: %s; height: %s; top: %s; left: %s;\"",
+			formatFloat(n.GetComputedWidth()),
+			formatFloat(n.GetComputedHeight()),
+			formatFloat(n.GetComputedTop()),
+			formatFloat(n.GetComputedLeft()))
+	}
+	if opts&PrintOptionsStyle != 0 {
+		str.WriteString(" style=\"")
+		for i, decl := range styleDeclarations(n, ref) {
+			if i > 0 {
+				str.WriteByte(' ')
+			}
+			fmt.Fprintf(str, "%s: %s;", decl.Property, decl.Value)
+		}
+		str.WriteByte('"')
+	}
+	if n.GetMeasureFunc() != nil {
+		str.WriteString(" has-custom-measure=\"true\"")
+	}
+	str.WriteString(" >")
+
+	childCount := n.GetChildCount()
+	if opts&PrintOptionsChildren != 0 && childCount > 0 {
+		for i := uint32(0); i < childCount; i++ {
+			str.WriteByte('\n')
+			n.GetChild(i).printNode(str, ref, opts, level+1)
+		}
+		str.WriteByte('\n')
+		n.indent(str, level)
+	}
+	str.WriteString("</div>")
+}
+
+// Format implements fmt.Formatter. %v and %s print the node's layout and
+// style, %+v also prints its children.
+func (n *Node) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		opts := PrintOptionsLayout | PrintOptionsStyle
+		if f.Flag('+') {
+			opts |= PrintOptionsChildren
+		}
+		io.WriteString(f, n.DebugString(opts))
+	default:
+		fmt.Fprintf(f, "%%!%c(*yoga.Node)", verb)
+	}
+}
@@ -0,0 +1,39 @@
+package yoga
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestNodeDebugString(t *testing.T) {
+	r := assert.New(t)
+	root := NewNode()
+	defer root.Destroy()
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(100)
+	root.SetHeight(50)
+	root.SetID("root")
+
+	child := NewNode()
+	defer child.Destroy()
+	child.SetFlexGrow(1)
+	child.SetMarginPercent(EdgeLeft, 10)
+	child.AddClass("item")
+	root.InsertChild(child, 0)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	r.Equal(`<div id="root" layout="width: 100; height: 50; top: 0; left: 0;" ></div>`, root.DebugString(PrintOptionsLayout))
+	r.Equal(`<div id="root" style="flex-direction: row; width: 100; height: 50;" >
+  <div class="item" style="flex-grow: 1; margin-left: 10%;" ></div>
+</div>`, root.DebugString(PrintOptionsStyle|PrintOptionsChildren))
+
+	r.Equal(`<div class="item" layout="width: 90; height: 50; top: 0; left: 10;" style="flex-grow: 1; margin-left: 10%;" ></div>`,
+		fmt.Sprintf("%v", child))
+	r.Equal(root.DebugString(PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren), fmt.Sprintf("%+v", root))
+	r.Equal("%!d(*yoga.Node)", fmt.Sprintf("%d", root))
+
+	var nilNode *Node
+	r.Equal("<nil>", fmt.Sprint(nilNode))
+}
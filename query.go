@@ -0,0 +1,89 @@
+package yoga
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FindByID returns the first node with the given id among the node and its
+// descendants in document order, or nil when there is none
+func (n *Node) FindByID(id string) *Node {
+	var found *Node
+	walkElements(n, func(e *element) {
+		if found == nil && e.id == id {
+			found = e.node
+		}
+	})
+	return found
+}
+
+// QuerySelectorAll returns the nodes matching a selector group such as
+// "#sidebar > .item:nth-child(2)" in document order. The node itself is the
+// top of the tree for matching and can match too. Supported are id and
+// class selectors, the descendant and child combinators and the
+// :first-child, :last-child and :nth-child(an+b) pseudo-classes.
+func (n *Node) QuerySelectorAll(selector string) ([]*Node, error) {
+	group, err := parseSelectorGroup(selector)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*Node
+	walkElements(n, func(e *element) {
+		if slices.ContainsFunc(group, func(s complexSelector) bool { return s.matches(e) }) {
+			nodes = append(nodes, e.node)
+		}
+	})
+	return nodes, nil
+}
+
+// QuerySelector returns the first node matching selector, or nil when no
+// node matches, see QuerySelectorAll
+func (n *Node) QuerySelector(selector string) (*Node, error) {
+	nodes, err := n.QuerySelectorAll(selector)
+	if err != nil || len(nodes) == 0 {
+		return nil, err
+	}
+	return nodes[0], nil
+}
+
+// Path returns the index path of the node from the root of its tree, such
+// as "0/3/1" for the second child of the fourth child of the first child
+// of the root. The path of the root is "".
+func (n *Node) Path() string {
+	if n.node == nil {
+		return ""
+	}
+	var indexes []string
+	for child, parent := n, n.GetParent(); parent != nil; child, parent = parent, parent.GetParent() {
+		childCount := parent.GetChildCount()
+		for i := uint32(0); i < childCount; i++ {
+			if parent.GetChild(i).node == child.node {
+				indexes = append(indexes, strconv.Itoa(int(i)))
+				break
+			}
+		}
+	}
+	slices.Reverse(indexes)
+	return strings.Join(indexes, "/")
+}
+
+// FindByPath returns the node at an index path below the node as returned
+// by Path, or nil when the path does not exist
+func (n *Node) FindByPath(path string) *Node {
+	if n.node == nil {
+		return nil
+	}
+	node := n
+	if path == "" {
+		return node
+	}
+	for _, part := range strings.Split(path, "/") {
+		i, err := strconv.Atoi(part)
+		if err != nil || i < 0 || uint32(i) >= node.GetChildCount() {
+			return nil
+		}
+		node = node.GetChild(uint32(i))
+	}
+	return node
+}
@@ -0,0 +1,55 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestQuery(t *testing.T) {
+	r := assert.New(t)
+	root, _ := Build(nil, Row(ID("app"),
+		Column(ID("sidebar"),
+			Box(Classes("item")),
+			Box(Classes("item", "active")),
+			Box(Classes("item")),
+			Box(Classes("item"), ID("last")),
+		),
+		Column(ID("main"), Box(Classes("item"))),
+	))
+	defer root.FreeRecursive()
+
+	last := root.FindByID("last")
+	r.NotNil(last)
+	r.Equal("0/3", last.Path())
+	r.Equal("", root.Path())
+	r.Equal(true, root.FindByID("missing") == nil)
+	r.Equal(true, root.FindByID("app").node == root.node)
+	r.Equal(true, root.FindByPath("0/3").node == last.node)
+	r.Equal(true, root.FindByPath("0/4") == nil)
+	r.Equal(true, root.FindByPath("x") == nil)
+
+	paths := func(selector string) []string {
+		nodes, err := root.QuerySelectorAll(selector)
+		r.Equal(nil, err)
+		var p []string
+		for _, n := range nodes {
+			p = append(p, n.Path())
+		}
+		return p
+	}
+	r.Equal([]string{"0/1"}, paths("#sidebar > .item:nth-child(2)"))
+	r.Equal([]string{"0/0", "0/2"}, paths("#sidebar .item:nth-child(odd)"))
+	r.Equal([]string{"0/1", "0/3"}, paths(".item:nth-child(2n)"))
+	r.Equal([]string{"0/0", "0/1", "1/0"}, paths("#app .item:nth-child(-n + 2)"))
+	r.Equal([]string{"0/3", "1"}, paths("#last, #main"))
+	r.Equal([]string(nil), paths("#main > .active"))
+
+	n, err := root.QuerySelector(".active")
+	r.Equal(nil, err)
+	r.Equal("0/1", n.Path())
+	_, err = root.QuerySelectorAll(":nth-child(x)")
+	r.NotNil(err)
+	_, err = root.QuerySelectorAll(":nth-child")
+	r.NotNil(err)
+}
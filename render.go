@@ -0,0 +1,69 @@
+package yoga
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// layoutBox is a node with its border box in the coordinates of the root's
+// parent, as used by the renderers
+type layoutBox struct {
+	node   *Node
+	parent *layoutBox
+	path   string
+	depth  int
+	x, y   float32
+	layout Layout
+}
+
+// edges returns the computed left, top, right and bottom values of get
+func edges(get func(Edge) float32) [4]float32 {
+	return [4]float32{get(EdgeLeft), get(EdgeTop), get(EdgeRight), get(EdgeBottom)}
+}
+
+// layoutBoxes lists root and its descendants in pre-order, which is also
+// the paint order. Nodes with display none and their subtrees are skipped.
+func layoutBoxes(root *Node) []*layoutBox {
+	if root == nil || root.node == nil {
+		return nil
+	}
+	var boxes []*layoutBox
+	var walk func(n *Node, parent *layoutBox, path string, depth int)
+	walk = func(n *Node, parent *layoutBox, path string, depth int) {
+		if n.GetDisplay() == DisplayNone {
+			return
+		}
+		box := &layoutBox{node: n, parent: parent, path: path, depth: depth, layout: n.GetComputedLayout()}
+		box.x, box.y = box.layout.Left, box.layout.Top
+		if parent != nil {
+			box.x += parent.x
+			box.y += parent.y
+		}
+		boxes = append(boxes, box)
+		childCount := n.GetChildCount()
+		for i := uint32(0); i < childCount; i++ {
+			childPath := strconv.Itoa(int(i))
+			if path != "" {
+				childPath = path + "/" + childPath
+			}
+			walk(n.GetChild(i), box, childPath, depth+1)
+		}
+	}
+	walk(root, nil, "", 0)
+	return boxes
+}
+
+// defaultLabel labels a node with its id, or its context when that is a
+// string or a fmt.Stringer
+func defaultLabel(n *Node) string {
+	if id := n.GetID(); id != "" {
+		return id
+	}
+	switch ctx := n.GetContext().(type) {
+	case string:
+		return ctx
+	case fmt.Stringer:
+		return ctx.String()
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package yoga
+
+import (
+	"fmt"
+	"slices"
+)
+
+// responsivePasses limits the extra layout passes of container queries, a
+// variant changing the width its container query depends on could
+// otherwise switch back and forth forever
+const responsivePasses = 4
+
+// StyleVariant is a set of style overrides applied by
+// CalculateResponsiveLayout at some widths, like a CSS media query
+type StyleVariant struct {
+	// MinWidth and MaxWidth are the inclusive bounds of the width the
+	// variant applies at, 0 means unbounded
+	MinWidth, MaxWidth float32
+	// Container matches the content width of the parent, its computed
+	// width minus padding and border, like a CSS container query, instead
+	// of the available width of the layout
+	Container bool
+	// Style is an inline style such as "flex-direction: column; padding: 8"
+	Style string
+}
+
+// matches reports whether the variant applies at width, an undefined
+// width only matches variants without bounds
+func (v StyleVariant) matches(width float32) bool {
+	if v.MinWidth > 0 && !(width >= v.MinWidth) {
+		return false
+	}
+	if v.MaxWidth > 0 && !(width <= v.MaxWidth) {
+		return false
+	}
+	return true
+}
+
+// styleVariant is a StyleVariant with its parsed declarations
+type styleVariant struct {
+	StyleVariant
+	decls []Declaration
+}
+
+// AddStyleVariant adds a style variant to the node. Variants matching the
+// same width are applied in the order they were added on top of the base
+// style, the style of the node when no variant applies.
+func (n *Node) AddStyleVariant(v StyleVariant) error {
+	if n.node == nil {
+		return fmt.Errorf("nil node")
+	}
+	decls, err := ParseDeclarations(v.Style)
+	if err != nil {
+		return fmt.Errorf("style variant: %w", err)
+	}
+	// 先用一个临时节点检查属性是否合法
+	check := NewNodeWithConfig(n.GetConfig())
+	defer check.Destroy()
+	for _, decl := range decls {
+		if err := check.SetStyleProperty(decl.Property, decl.Value); err != nil {
+			return fmt.Errorf("style variant: %w", err)
+		}
+	}
+	attrs := getNodeAttrs(n.node, true)
+	attrs.variants = append(attrs.variants, styleVariant{StyleVariant: v, decls: decls})
+	return nil
+}
+
+// GetStyleVariants gets the style variants of the node
+func (n *Node) GetStyleVariants() []StyleVariant {
+	attrs := getNodeAttrs(n.node, false)
+	if attrs == nil {
+		return nil
+	}
+	variants := make([]StyleVariant, len(attrs.variants))
+	for i, v := range attrs.variants {
+		variants[i] = v.StyleVariant
+	}
+	return variants
+}
+
+// ClearStyleVariants removes the style variants of the node and restores
+// its base style
+func (n *Node) ClearStyleVariants() {
+	attrs := getNodeAttrs(n.node, false)
+	if attrs == nil {
+		return
+	}
+	if attrs.baseStyle != nil {
+		n.CopyStyle(attrs.baseStyle)
+		attrs.baseStyle.Destroy()
+	}
+	attrs.variants, attrs.activeVariants, attrs.baseStyle = nil, nil, nil
+}
+
+// CalculateResponsiveLayout applies the style variants of root and its
+// descendants that match width and then calculates the layout like
+// CalculateLayout. Nodes are only marked dirty when the set of matching
+// variants changes their style.
+//
+// When variants are applied the base style of the node is saved, and it is
+// restored when they stop matching; style changes made while a variant is
+// applied are lost at the next switch. Container variants are matched
+// against the content width of the parent (width for root) and need a
+// layout pass first, CalculateResponsiveLayout lays out again until no
+// variant changes, at most a few times.
+func CalculateResponsiveLayout(root *Node, width, height float32, direction Direction) {
+	if root == nil || root.node == nil {
+		return
+	}
+	r := &responsive{width: width, scratch: NewNodeWithConfig(root.GetConfig())}
+	defer r.scratch.Destroy()
+	r.apply(root, width)
+	root.CalculateLayout(width, height, direction)
+	for i := 0; i < responsivePasses && r.container; i++ {
+		r.container = false
+		if !r.apply(root, width) {
+			break
+		}
+		root.CalculateLayout(width, height, direction)
+	}
+}
+
+type responsive struct {
+	width   float32
+	scratch *Node
+	// container 记录是否存在容器查询
+	container bool
+}
+
+// apply applies the matching variants to n and its descendants and reports
+// whether any node changed its variants
+func (r *responsive) apply(n *Node, parentWidth float32) bool {
+	changed := false
+	if attrs := getNodeAttrs(n.node, false); attrs != nil && len(attrs.variants) > 0 {
+		var active []int
+		for i, v := range attrs.variants {
+			width := r.width
+			if v.Container {
+				r.container = true
+				width = parentWidth
+			}
+			if v.matches(width) {
+				active = append(active, i)
+			}
+		}
+		if !slices.Equal(active, attrs.activeVariants) {
+			if attrs.baseStyle == nil {
+				// 应用变体前保存基础样式
+				attrs.baseStyle = newChildNode(n.GetConfig())
+				attrs.baseStyle.CopyStyle(n)
+			}
+			r.scratch.CopyStyle(attrs.baseStyle)
+			for _, i := range active {
+				for _, decl := range attrs.variants[i].decls {
+					// 声明在 AddStyleVariant 中已经检查过
+					_ = r.scratch.SetStyleProperty(decl.Property, decl.Value)
+				}
+			}
+			n.CopyStyle(r.scratch)
+			attrs.activeVariants = active
+			if len(active) == 0 {
+				// 已恢复基础样式，下次应用变体时重新保存
+				attrs.baseStyle.Destroy()
+				attrs.baseStyle = nil
+			}
+			changed = true
+		}
+	}
+	// 子节点的容器查询使用内容盒的宽度
+	width := n.GetComputedWidth() -
+		n.GetComputedPadding(EdgeLeft) - n.GetComputedPadding(EdgeRight) -
+		n.GetComputedBorder(EdgeLeft) - n.GetComputedBorder(EdgeRight)
+	childCount := n.GetChildCount()
+	for i := uint32(0); i < childCount; i++ {
+		if r.apply(n.GetChild(i), width) {
+			changed = true
+		}
+	}
+	return changed
+}
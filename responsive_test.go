@@ -0,0 +1,95 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestCalculateResponsiveLayout(t *testing.T) {
+	r := assert.New(t)
+	root, nodes := Build(nil, Row(Padding(20),
+		Column(ID("sidebar"), Width(200)),
+		Column(ID("main"), Flex(1),
+			Box(ID("card"), Width(100), Height(50)),
+			Box(ID("card2"), Width(100), Height(50)),
+		),
+	))
+	defer root.FreeRecursive()
+	r.Equal(nil, root.AddStyleVariant(StyleVariant{MaxWidth: 600, Style: "flex-direction: column; padding: 4"}))
+	r.Equal(nil, nodes["sidebar"].AddStyleVariant(StyleVariant{MaxWidth: 600, Style: "width: auto; height: 40"}))
+	r.Equal(nil, nodes["main"].AddStyleVariant(StyleVariant{Container: true, MinWidth: 500, Style: "flex-direction: row"}))
+	r.NotNil(root.AddStyleVariant(StyleVariant{Style: "colour: red"}))
+	r.Equal(1, len(root.GetStyleVariants()))
+
+	CalculateResponsiveLayout(root, 1000, Undefined, DirectionLTR)
+	r.Equal(FlexDirectionRow, root.GetFlexDirection())
+	r.Equal(float32(200), nodes["sidebar"].GetComputedWidth())
+	// main 的父节点宽 1000，容器查询生效
+	r.Equal(FlexDirectionRow, nodes["main"].GetFlexDirection())
+	r.Equal(float32(100), nodes["card2"].GetComputedLeft())
+
+	// 匹配的变体不变时不会标记 dirty
+	rs := &responsive{width: 800, scratch: NewNodeWithConfig(root.GetConfig())}
+	defer rs.scratch.Destroy()
+	r.False(rs.apply(root, 800))
+	r.False(root.IsDirty())
+	rs.width = 400
+	r.True(rs.apply(root, 400))
+	r.True(nodes["sidebar"].IsDirty())
+	r.False(nodes["card"].IsDirty())
+
+	CalculateResponsiveLayout(root, 400, Undefined, DirectionLTR)
+	r.Equal(FlexDirectionColumn, root.GetFlexDirection())
+	r.Equal(float32(4), root.GetComputedPadding(EdgeLeft))
+	r.Equal(float32(392), nodes["sidebar"].GetComputedWidth())
+	r.Equal(float32(40), nodes["sidebar"].GetComputedHeight())
+	r.Equal(FlexDirectionColumn, nodes["main"].GetFlexDirection())
+	r.Equal(float32(50), nodes["card2"].GetComputedTop())
+
+	CalculateResponsiveLayout(root, 1000, Undefined, DirectionLTR)
+	r.Equal(float32(20), root.GetComputedPadding(EdgeLeft))
+	r.Equal(float32(200), nodes["sidebar"].GetComputedWidth())
+	r.Equal(FlexDirectionRow, nodes["main"].GetFlexDirection())
+
+	root.ClearStyleVariants()
+	r.Equal(0, len(root.GetStyleVariants()))
+	CalculateResponsiveLayout(root, 400, Undefined, DirectionLTR)
+	r.Equal(FlexDirectionRow, root.GetFlexDirection())
+}
+
+func TestStyleVariantBaseStyle(t *testing.T) {
+	r := assert.New(t)
+	root, nodes := Build(nil, Column(Width(520), Padding(10), Border(5),
+		Box(ID("box"), Height(10)),
+	))
+	defer root.FreeRecursive()
+	box := nodes["box"]
+	// 父节点内容盒宽 490，容器查询不匹配
+	r.Equal(nil, box.AddStyleVariant(StyleVariant{MaxWidth: 600, Style: "height: 20"}))
+	r.Equal(nil, box.AddStyleVariant(StyleVariant{Container: true, MinWidth: 500, Style: "height: 30"}))
+
+	CalculateResponsiveLayout(root, 520, Undefined, DirectionLTR)
+	r.Equal(float32(20), box.GetComputedHeight())
+	attrs := getNodeAttrs(box.node, false)
+	r.NotNil(attrs.baseStyle)
+
+	// 克隆有自己的基础样式节点
+	clone := box.Clone()
+	cloned := getNodeAttrs(clone.node, false).baseStyle
+	r.NotNil(cloned)
+	r.False(cloned.node == attrs.baseStyle.node)
+	r.Equal(Value{Value: 10, Unit: UnitPoint}, cloned.GetHeight())
+	clone.Destroy()
+
+	// 没有匹配的变体时恢复并释放基础样式
+	CalculateResponsiveLayout(root, 800, Undefined, DirectionLTR)
+	r.Equal(float32(10), box.GetComputedHeight())
+	r.True(attrs.baseStyle == nil)
+
+	CalculateResponsiveLayout(root, 520, Undefined, DirectionLTR)
+	r.NotNil(attrs.baseStyle)
+	box.ClearStyleVariants()
+	r.True(attrs.baseStyle == nil)
+	r.Equal(Value{Value: 10, Unit: UnitPoint}, box.GetHeight())
+}
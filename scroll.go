@@ -0,0 +1,75 @@
+package yoga
+
+// Viewport returns the padding box of the node relative to its border box,
+// the area a scroll container shows its content in
+func (n *Node) Viewport() Rect {
+	if n.node == nil {
+		return Rect{}
+	}
+	b := edges(n.GetComputedBorder)
+	return Rect{
+		X:      b[0],
+		Y:      b[1],
+		Width:  max(n.GetComputedWidth()-b[0]-b[2], 0),
+		Height: max(n.GetComputedHeight()-b[1]-b[3], 0),
+	}
+}
+
+// ContentSize returns the size of the scrollable content of the node like
+// the DOM scrollWidth and scrollHeight: the extent of the margin boxes of
+// its children plus the right and bottom padding, measured from the top
+// left of the padding box and at least the size of the padding box.
+// Children with display none are ignored. The maximum scroll offset is the
+// content size minus the size of the Viewport.
+func (n *Node) ContentSize() Size {
+	viewport := n.Viewport()
+	size := Size{Width: viewport.Width, Height: viewport.Height}
+	if n.node == nil {
+		return size
+	}
+	p := edges(n.GetComputedPadding)
+	childCount := n.GetChildCount()
+	for i := uint32(0); i < childCount; i++ {
+		child := n.GetChild(i)
+		if child.GetDisplay() == DisplayNone {
+			continue
+		}
+		m := edges(child.GetComputedMargin)
+		right := child.GetComputedLeft() + child.GetComputedWidth() + m[2] + p[2] - viewport.X
+		bottom := child.GetComputedTop() + child.GetComputedHeight() + m[3] + p[3] - viewport.Y
+		size.Width = max(size.Width, right)
+		size.Height = max(size.Height, bottom)
+	}
+	return size
+}
+
+// VisibleChildren returns the children whose border boxes, moved by the
+// scroll offset of the node, overlap viewport, in the coordinates of the
+// node's border box. Pass Viewport() to cull the children of a scroll
+// container, or a smaller rect when the container is only partly on
+// screen. Children with display none are skipped.
+func (n *Node) VisibleChildren(viewport Rect) []*Node {
+	if n.node == nil || viewport.Empty() {
+		return nil
+	}
+	scrollX, scrollY := n.GetScrollOffset()
+	var visible []*Node
+	childCount := n.GetChildCount()
+	for i := uint32(0); i < childCount; i++ {
+		child := n.GetChild(i)
+		if child.GetDisplay() == DisplayNone {
+			continue
+		}
+		box := Rect{
+			X:      child.GetComputedLeft() - scrollX,
+			Y:      child.GetComputedTop() - scrollY,
+			Width:  child.GetComputedWidth(),
+			Height: child.GetComputedHeight(),
+		}
+		// 零尺寸的子节点按所在位置判断
+		if !box.Intersect(viewport).Empty() || box.Empty() && viewport.Contains(box.X, box.Y) {
+			visible = append(visible, child)
+		}
+	}
+	return visible
+}
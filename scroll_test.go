@@ -0,0 +1,42 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestScrollContainer(t *testing.T) {
+	r := assert.New(t)
+	items := []Option{ID("list"), Width(100), Height(100), OverflowOption(OverflowScroll), Border(2), Padding(4)}
+	for i := 0; i < 1000; i++ {
+		items = append(items, Box(Height(20), MarginEdge(EdgeBottom, 5)))
+	}
+	items = append(items, Box(ID("hidden"), Height(20), DisplayOption(DisplayNone)))
+	root, nodes := Build(nil, Column(items...))
+	defer root.FreeRecursive()
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	list := nodes["list"]
+	r.Equal(Rect{X: 2, Y: 2, Width: 96, Height: 96}, list.Viewport())
+	// 4 + 1000*25 + 4
+	r.Equal(Size{Width: 96, Height: 25008}, list.ContentSize())
+
+	visible := list.VisibleChildren(list.Viewport())
+	r.Equal(4, len(visible))
+	r.Equal("0", visible[0].Path())
+
+	list.SetScrollOffset(0, 500)
+	visible = list.VisibleChildren(list.Viewport())
+	r.Equal(4, len(visible))
+	r.Equal("20", visible[0].Path())
+	r.Equal("23", visible[3].Path())
+	r.Equal(float32(6), visible[0].AbsoluteRect().Y)
+
+	r.Equal(0, len(list.VisibleChildren(Rect{X: 200, Y: 0, Width: 10, Height: 10})))
+
+	wide, _ := Build(nil, Row(Width(50), Height(10), Box(Width(80), MarginEdge(EdgeRight, 10))))
+	defer wide.FreeRecursive()
+	wide.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	r.Equal(Size{Width: 90, Height: 10}, wide.ContentSize())
+}
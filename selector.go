@@ -0,0 +1,286 @@
+package yoga
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// combinator joins two compound selectors
+type combinator int
+
+const (
+	combinatorDescendant combinator = iota
+	combinatorChild
+)
+
+// compoundSelector is a sequence of simple selectors without combinators, e.g. "#a.b:first-child"
+type compoundSelector struct {
+	id      string
+	classes []string
+	pseudos []pseudoClass
+}
+
+// pseudoClass is a structural pseudo-class, a and b are the arguments of
+// :nth-child(an+b)
+type pseudoClass struct {
+	name string
+	a, b int
+}
+
+// complexSelector is a chain of compound selectors joined by combinators;
+// combinators[i] joins parts[i] to parts[i+1]
+type complexSelector struct {
+	parts       []compoundSelector
+	combinators []combinator
+}
+
+// element is a node seen during a tree walk, with the structural
+// information selectors need
+type element struct {
+	node    *Node
+	parent  *element
+	index   int
+	count   int
+	id      string
+	classes []string
+}
+
+// parseSelectorGroup parses a comma separated list of complex selectors
+func parseSelectorGroup(src string) ([]complexSelector, error) {
+	var group []complexSelector
+	for _, part := range strings.Split(src, ",") {
+		sel, err := parseComplexSelector(part)
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, sel)
+	}
+	return group, nil
+}
+
+func parseComplexSelector(src string) (complexSelector, error) {
+	var sel complexSelector
+	// 去掉括号内的空白，例如 :nth-child(2n + 1)
+	var b strings.Builder
+	depth := 0
+	for _, r := range src {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth > 0 && (r == ' ' || r == '\t' || r == '\n'):
+			continue
+		}
+		b.WriteRune(r)
+	}
+	tokens := strings.Fields(strings.ReplaceAll(b.String(), ">", " > "))
+	pending := combinatorDescendant
+	for i, tok := range tokens {
+		if tok == ">" {
+			if len(sel.parts) == 0 || i == len(tokens)-1 || tokens[i+1] == ">" {
+				return complexSelector{}, fmt.Errorf("invalid selector: %s", strings.TrimSpace(src))
+			}
+			pending = combinatorChild
+			continue
+		}
+		compound, err := parseCompoundSelector(tok)
+		if err != nil {
+			return complexSelector{}, fmt.Errorf("invalid selector %q: %w", strings.TrimSpace(src), err)
+		}
+		if len(sel.parts) > 0 {
+			sel.combinators = append(sel.combinators, pending)
+		}
+		sel.parts = append(sel.parts, compound)
+		pending = combinatorDescendant
+	}
+	if len(sel.parts) == 0 {
+		return complexSelector{}, fmt.Errorf("empty selector")
+	}
+	return sel, nil
+}
+
+func parseCompoundSelector(src string) (compoundSelector, error) {
+	var c compoundSelector
+	if strings.HasPrefix(src, "*") {
+		src = src[1:]
+	}
+	for len(src) > 0 {
+		kind := src[0]
+		end := 1
+		for end < len(src) && isSelectorNameChar(src[end]) {
+			end++
+		}
+		name := src[1:end]
+		src = src[end:]
+		if name == "" {
+			return c, fmt.Errorf("missing name after %q", kind)
+		}
+		switch kind {
+		case '#':
+			if c.id != "" && c.id != name {
+				return c, fmt.Errorf("multiple ids")
+			}
+			c.id = name
+		case '.':
+			c.classes = append(c.classes, name)
+		case ':':
+			pseudo := pseudoClass{name: name}
+			switch name {
+			case "first-child", "last-child":
+			case "nth-child":
+				end := strings.IndexByte(src, ')')
+				if !strings.HasPrefix(src, "(") || end < 0 {
+					return c, fmt.Errorf("missing argument of :%s", name)
+				}
+				a, b, err := parseNth(src[1:end])
+				if err != nil {
+					return c, fmt.Errorf("invalid argument of :%s: %w", name, err)
+				}
+				pseudo.a, pseudo.b = a, b
+				src = src[end+1:]
+			default:
+				return c, fmt.Errorf("unsupported pseudo-class :%s", name)
+			}
+			c.pseudos = append(c.pseudos, pseudo)
+		default:
+			return c, fmt.Errorf("unexpected %q", kind)
+		}
+	}
+	return c, nil
+}
+
+// parseNth parses the an+b argument of :nth-child, including odd and even
+func parseNth(s string) (a, b int, err error) {
+	switch s {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+	i := strings.IndexByte(s, 'n')
+	if i < 0 {
+		b, err = strconv.Atoi(s)
+		return 0, b, err
+	}
+	switch coef := s[:i]; coef {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		if a, err = strconv.Atoi(coef); err != nil {
+			return 0, 0, err
+		}
+	}
+	if rest := s[i+1:]; rest != "" {
+		if rest[0] != '+' && rest[0] != '-' {
+			return 0, 0, fmt.Errorf("unexpected %q", rest)
+		}
+		if b, err = strconv.Atoi(rest); err != nil {
+			return 0, 0, err
+		}
+	}
+	return a, b, nil
+}
+
+// matchesNth reports whether the 1-based position is a*n+b for some n >= 0
+func matchesNth(a, b, position int) bool {
+	if a == 0 {
+		return position == b
+	}
+	return (position-b)%a == 0 && (position-b)/a >= 0
+}
+
+func isSelectorNameChar(b byte) bool {
+	return b == '-' || b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// specificity returns the selector specificity packed as ids*10000 + classes
+func (s complexSelector) specificity() int {
+	spec := 0
+	for _, part := range s.parts {
+		if part.id != "" {
+			spec += 10000
+		}
+		spec += len(part.classes) + len(part.pseudos)
+	}
+	return spec
+}
+
+// matches reports whether the selector matches e
+func (s complexSelector) matches(e *element) bool {
+	return s.matchFrom(len(s.parts)-1, e)
+}
+
+func (s complexSelector) matchFrom(i int, e *element) bool {
+	if !s.parts[i].matches(e) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	if s.combinators[i-1] == combinatorChild {
+		return e.parent != nil && s.matchFrom(i-1, e.parent)
+	}
+	for p := e.parent; p != nil; p = p.parent {
+		if s.matchFrom(i-1, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c compoundSelector) matches(e *element) bool {
+	if c.id != "" && c.id != e.id {
+		return false
+	}
+	for _, class := range c.classes {
+		if !slices.Contains(e.classes, class) {
+			return false
+		}
+	}
+	for _, pseudo := range c.pseudos {
+		if e.parent == nil {
+			return false
+		}
+		switch pseudo.name {
+		case "first-child":
+			if e.index != 0 {
+				return false
+			}
+		case "last-child":
+			if e.index != e.count-1 {
+				return false
+			}
+		case "nth-child":
+			if !matchesNth(pseudo.a, pseudo.b, e.index+1) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// walkElements visits root and its descendants in document order;
+// root is treated as the top of the tree even if it has a parent
+func walkElements(root *Node, visit func(e *element)) {
+	if root == nil || root.node == nil {
+		return
+	}
+	walkElement(&element{node: root, count: 1}, visit)
+}
+
+func walkElement(e *element, visit func(e *element)) {
+	if attrs := getNodeAttrs(e.node.node, false); attrs != nil {
+		e.id = attrs.id
+		e.classes = attrs.classes
+	}
+	visit(e)
+	count := int(e.node.GetChildCount())
+	for i := 0; i < count; i++ {
+		walkElement(&element{node: e.node.GetChild(uint32(i)), parent: e, index: i, count: count}, visit)
+	}
+}
@@ -0,0 +1,830 @@
+package yoga
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"strconv"
+)
+
+// TreeSnapshot is a detached copy of a tree's config, styles and optionally
+// its computed layout. It is the in-memory form of both the binary encoding
+// (EncodeTree/DecodeTree) and the JSON form (encoding/json).
+type TreeSnapshot struct {
+	Config ConfigSnapshot `json:"config"`
+	Root   *NodeSnapshot  `json:"root"`
+}
+
+// ConfigSnapshot holds the config values of a snapshot
+type ConfigSnapshot struct {
+	PointScaleFactor     float32               `json:"pointScaleFactor"`
+	Errata               Errata                `json:"errata"`
+	UseWebDefaults       bool                  `json:"useWebDefaults,omitempty"`
+	ExperimentalFeatures []ExperimentalFeature `json:"experimentalFeatures,omitempty"`
+}
+
+// NodeSnapshot holds a node's identity, its non-default style properties
+// and, if captured, its computed layout
+type NodeSnapshot struct {
+	ID       string          `json:"id,omitempty"`
+	Classes  []string        `json:"classes,omitempty"`
+	Style    []Declaration   `json:"style,omitempty"`
+	Layout   *Layout         `json:"layout,omitempty"`
+	Children []*NodeSnapshot `json:"children,omitempty"`
+}
+
+// experimentalFeatures lists all known experimental features
+var experimentalFeatures = []ExperimentalFeature{ExperimentalFeatureWebFlexBasis}
+
+// NewTreeSnapshot captures root and its descendants. Only style properties
+// that differ from a fresh node with the root's config are recorded.
+func NewTreeSnapshot(root *Node, includeLayout bool) *TreeSnapshot {
+	if root == nil || root.node == nil {
+		return nil
+	}
+	config := root.GetConfig()
+	s := &TreeSnapshot{Config: configSnapshot(config)}
+	ref := NewNodeWithConfig(config)
+	defer ref.Destroy()
+	s.Root = snapshotNode(root, ref, includeLayout)
+	return s
+}
+
+func configSnapshot(config *Config) ConfigSnapshot {
+	c := ConfigSnapshot{
+		PointScaleFactor: config.PointScaleFactor(),
+		Errata:           config.GetErrata(),
+		UseWebDefaults:   config.UseWebDefaults(),
+	}
+	for _, feature := range experimentalFeatures {
+		if config.IsExperimentalFeatureEnabled(feature) {
+			c.ExperimentalFeatures = append(c.ExperimentalFeatures, feature)
+		}
+	}
+	return c
+}
+
+// newConfig creates a config with the values of the snapshot
+func (c ConfigSnapshot) newConfig() *Config {
+	config := NewConfig()
+	config.SetPointScaleFactor(c.PointScaleFactor)
+	config.SetErrata(c.Errata)
+	config.SetUseWebDefaults(c.UseWebDefaults)
+	for _, feature := range c.ExperimentalFeatures {
+		config.SetExperimentalFeatureEnabled(feature, true)
+	}
+	return config
+}
+
+func snapshotNode(n, ref *Node, includeLayout bool) *NodeSnapshot {
+	s := &NodeSnapshot{
+		ID:      n.GetID(),
+		Classes: n.GetClasses(),
+		Style:   styleDeclarations(n, ref),
+	}
+	if includeLayout {
+		layout := n.GetComputedLayout()
+		s.Layout = &layout
+	}
+	count := n.GetChildCount()
+	if count > 0 {
+		s.Children = make([]*NodeSnapshot, count)
+		for i := uint32(0); i < count; i++ {
+			s.Children[i] = snapshotNode(n.GetChild(i), ref, includeLayout)
+		}
+	}
+	return s
+}
+
+// Build creates a new tree from the snapshot. The returned root owns its
+// descendants and its config; release the whole tree with FreeRecursive.
+// Computed layouts in the snapshot are not applied, call CalculateLayout.
+func (s *TreeSnapshot) Build() (*Node, error) {
+	if s == nil || s.Root == nil {
+		return nil, errors.New("empty snapshot")
+	}
+	config := s.Config.newConfig()
+	root := NewNodeWithConfig(config)
+	if err := s.Root.apply(root, config); err != nil {
+		root.FreeRecursive()
+		return nil, err
+	}
+	return root, nil
+}
+
+func (s *NodeSnapshot) apply(n *Node, config *Config) error {
+	if s.ID != "" {
+		n.SetID(s.ID)
+	}
+	if len(s.Classes) > 0 {
+		n.SetClasses(s.Classes...)
+	}
+	for _, decl := range s.Style {
+		if err := n.SetStyleProperty(decl.Property, decl.Value); err != nil {
+			return err
+		}
+	}
+	for i, child := range s.Children {
+		c := newChildNode(config)
+		n.InsertChild(c, uint32(i))
+		if err := child.apply(c, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Binary snapshot format
+//
+//	header   magic "YGSN", major version byte, minor version byte, flags uvarint
+//	config   fields terminated by tag 0
+//	nodes    pre-order; each node is its fields terminated by tag 0,
+//	         followed by its child count (uvarint) and its children
+//	trailer  CRC-32C of everything before it, little endian
+//
+// A field is tag (uvarint), payload length (uvarint) and payload, so readers
+// skip tags and style properties they don't know. New fields only bump the
+// minor version; a different major version is rejected.
+//
+// A style field is the property code (uvarint), the value kind (byte) and
+// the value: a float32 for numbers and percentages, a uvarint for enums and
+// nothing for keywords such as auto.
+const (
+	snapshotMagic        = "YGSN"
+	snapshotMajorVersion = 1
+	snapshotMinorVersion = 0
+
+	snapshotFlagLayout = 1 << 0
+)
+
+// config field tags
+const (
+	configTagPointScaleFactor = 1 + iota
+	configTagErrata
+	configTagUseWebDefaults
+	configTagExperimentalFeature
+)
+
+// node field tags
+const (
+	nodeTagID = 1 + iota
+	nodeTagClass
+	nodeTagStyle
+	nodeTagLayout
+)
+
+// style value kinds
+const (
+	styleKindNumber = iota
+	styleKindPercent
+	styleKindEnum
+	styleKindUndefined
+	styleKindAuto
+	styleKindMaxContent
+	styleKindFitContent
+	styleKindStretch
+)
+
+// styleValue is a typed style value of the binary format. Values are
+// normalized so that equal values compare equal with ==.
+type styleValue struct {
+	kind byte
+	num  float32
+	enum uint64
+}
+
+func numberValue(f float32) styleValue {
+	if IsNaN(f) {
+		return styleValue{kind: styleKindUndefined}
+	}
+	return styleValue{kind: styleKindNumber, num: f}
+}
+
+func (v styleValue) number() (float32, error) {
+	switch v.kind {
+	case styleKindNumber:
+		return v.num, nil
+	case styleKindUndefined:
+		return Undefined, nil
+	}
+	return 0, fmt.Errorf("unexpected style value kind %d", v.kind)
+}
+
+// lengthKinds maps the units of lengths to their value kinds
+var lengthKinds = map[Unit]byte{
+	UnitUndefined:  styleKindUndefined,
+	UnitPoint:      styleKindNumber,
+	UnitPercent:    styleKindPercent,
+	UnitAuto:       styleKindAuto,
+	UnitMaxContent: styleKindMaxContent,
+	UnitFitContent: styleKindFitContent,
+	UnitStretch:    styleKindStretch,
+}
+
+func lengthValue(v Value) styleValue {
+	kind := lengthKinds[v.Unit]
+	if kind != styleKindNumber && kind != styleKindPercent {
+		return styleValue{kind: kind}
+	}
+	return styleValue{kind: kind, num: v.Value}
+}
+
+func (v styleValue) length() (Value, error) {
+	for unit, kind := range lengthKinds {
+		if kind == v.kind {
+			if unit == UnitPoint || unit == UnitPercent {
+				return Value{Value: v.num, Unit: unit}, nil
+			}
+			return Value{Value: Undefined, Unit: unit}, nil
+		}
+	}
+	return Value{}, fmt.Errorf("unexpected style value kind %d", v.kind)
+}
+
+// snapshotProperty reads and writes a style property as a typed value.
+// parse and format convert between typed values and declaration values.
+type snapshotProperty struct {
+	name   string
+	get    func(n *Node) styleValue
+	set    func(n *Node, v styleValue) error
+	parse  func(value string) (styleValue, error)
+	format func(v styleValue) string
+}
+
+func enumProperty[T ~int](name string, get func(*Node) T, set func(*Node, T), parse func(string) (T, error), format func(T) string) snapshotProperty {
+	return snapshotProperty{
+		name: name,
+		get:  func(n *Node) styleValue { return styleValue{kind: styleKindEnum, enum: uint64(get(n))} },
+		set: func(n *Node, v styleValue) error {
+			if v.kind != styleKindEnum {
+				return fmt.Errorf("unexpected style value kind %d for %s", v.kind, name)
+			}
+			set(n, T(v.enum))
+			return nil
+		},
+		parse: func(value string) (styleValue, error) {
+			e, err := parse(value)
+			return styleValue{kind: styleKindEnum, enum: uint64(e)}, err
+		},
+		format: func(v styleValue) string { return format(T(v.enum)) },
+	}
+}
+
+func numberProperty(name string, get func(*Node) float32, set func(*Node, float32)) snapshotProperty {
+	return snapshotProperty{
+		name: name,
+		get:  func(n *Node) styleValue { return numberValue(get(n)) },
+		set: func(n *Node, v styleValue) error {
+			f, err := v.number()
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			set(n, f)
+			return nil
+		},
+		parse: func(value string) (styleValue, error) {
+			f, err := strconv.ParseFloat(value, 32)
+			return numberValue(float32(f)), err
+		},
+		format: func(v styleValue) string { return formatFloat(v.num) },
+	}
+}
+
+func lengthProperty(name string, get func(*Node) Value, setter func(n *Node) lengthSetter) snapshotProperty {
+	return snapshotProperty{
+		name: name,
+		get:  func(n *Node) styleValue { return lengthValue(get(n)) },
+		set: func(n *Node, v styleValue) error {
+			l, err := v.length()
+			if err == nil {
+				err = setter(n).apply(l)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return nil
+		},
+		parse: func(value string) (styleValue, error) {
+			l, err := ParseValue(value)
+			return lengthValue(l), err
+		},
+		format: func(v styleValue) string {
+			l, _ := v.length()
+			return l.String()
+		},
+	}
+}
+
+// snapshotProperties assigns the style property codes of the binary format.
+// The order is part of the format: only ever append to it.
+var snapshotProperties = func() []snapshotProperty {
+	props := []snapshotProperty{
+		enumProperty("direction", (*Node).GetDirection, (*Node).SetDirection, DirectionFromString, Direction.String),
+		enumProperty("flex-direction", (*Node).GetFlexDirection, (*Node).SetFlexDirection, FlexDirectionFromString, FlexDirection.String),
+		enumProperty("justify-content", (*Node).GetJustifyContent, (*Node).SetJustifyContent, JustifyFromString, Justify.String),
+		enumProperty("align-content", (*Node).GetAlignContent, (*Node).SetAlignContent, AlignFromString, Align.String),
+		enumProperty("align-items", (*Node).GetAlignItems, (*Node).SetAlignItems, AlignFromString, Align.String),
+		enumProperty("align-self", (*Node).GetAlignSelf, (*Node).SetAlignSelf, AlignFromString, Align.String),
+		enumProperty("position", (*Node).GetPositionType, (*Node).SetPositionType, PositionTypeFromString, PositionType.String),
+		enumProperty("flex-wrap", (*Node).GetFlexWrap, (*Node).SetFlexWrap, wrapFromCSS, wrapToCSS),
+		enumProperty("overflow", (*Node).GetOverflow, (*Node).SetOverflow, OverflowFromString, Overflow.String),
+		enumProperty("display", (*Node).GetDisplay, (*Node).SetDisplay, DisplayFromString, Display.String),
+		enumProperty("box-sizing", (*Node).GetBoxSizing, (*Node).SetBoxSizing, BoxSizingFromString, BoxSizing.String),
+		numberProperty("flex", (*Node).GetFlex, (*Node).SetFlex),
+		numberProperty("flex-grow", (*Node).GetFlexGrow, (*Node).SetFlexGrow),
+		numberProperty("flex-shrink", (*Node).GetFlexShrink, (*Node).SetFlexShrink),
+		numberProperty("aspect-ratio", (*Node).GetAspectRatio, (*Node).SetAspectRatio),
+		lengthProperty("flex-basis", (*Node).GetFlexBasis, (*Node).flexBasisSetter),
+		lengthProperty("width", (*Node).GetWidth, (*Node).widthSetter),
+		lengthProperty("height", (*Node).GetHeight, (*Node).heightSetter),
+		lengthProperty("min-width", (*Node).GetMinWidth, (*Node).minWidthSetter),
+		lengthProperty("min-height", (*Node).GetMinHeight, (*Node).minHeightSetter),
+		lengthProperty("max-width", (*Node).GetMaxWidth, (*Node).maxWidthSetter),
+		lengthProperty("max-height", (*Node).GetMaxHeight, (*Node).maxHeightSetter),
+	}
+	for _, gutter := range []Gutter{GutterAll, GutterRow, GutterColumn} {
+		name := "gap"
+		if gutter != GutterAll {
+			name = gutter.String() + "-gap"
+		}
+		props = append(props, lengthProperty(name, func(n *Node) Value { return n.GetGap(gutter) }, func(n *Node) lengthSetter { return gapSetter(n, gutter) }))
+	}
+	for _, edge := range allEdges {
+		props = append(props, lengthProperty(edgePropertyName("margin", edge),
+			func(n *Node) Value { return n.GetMargin(edge) }, func(n *Node) lengthSetter { return marginSetter(n, edge) }))
+	}
+	for _, edge := range allEdges {
+		props = append(props, lengthProperty(edgePropertyName("padding", edge),
+			func(n *Node) Value { return n.GetPadding(edge) }, func(n *Node) lengthSetter { return paddingSetter(n, edge) }))
+	}
+	for _, edge := range allEdges {
+		props = append(props, numberProperty(edgePropertyName("border", edge),
+			func(n *Node) float32 { return n.GetBorder(edge) }, func(n *Node, v float32) { n.SetBorder(edge, v) }))
+	}
+	for _, edge := range allEdges {
+		props = append(props, lengthProperty(edgePropertyName("position", edge),
+			func(n *Node) Value { return n.GetPosition(edge) }, func(n *Node) lengthSetter { return positionSetter(n, edge) }))
+	}
+	return props
+}()
+
+var snapshotPropertyCodes = func() map[string]uint64 {
+	codes := make(map[string]uint64, len(snapshotProperties))
+	for i, p := range snapshotProperties {
+		codes[p.name] = uint64(i)
+	}
+	return codes
+}()
+
+var snapshotCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// EncodeOption configures EncodeTree
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	includeLayout bool
+}
+
+// IncludeLayout makes EncodeTree store the computed layout of every node,
+// for read-only viewers using DecodeSnapshot
+func IncludeLayout() EncodeOption {
+	return func(o *encodeOptions) {
+		o.includeLayout = true
+	}
+}
+
+// EncodeTree writes the config and styles of root and its descendants in the
+// versioned binary snapshot format. It reads the styles directly from the
+// nodes, without going through a TreeSnapshot.
+func EncodeTree(w io.Writer, root *Node, opts ...EncodeOption) error {
+	var o encodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if root == nil || root.node == nil {
+		return errors.New("encode of nil node")
+	}
+	config := root.GetConfig()
+	e := &snapshotEncoder{}
+	e.header(configSnapshot(config), o.includeLayout)
+
+	// 与新节点的默认样式比较，只写出不同的属性
+	ref := NewNodeWithConfig(config)
+	defer ref.Destroy()
+	defaults := make([]styleValue, len(snapshotProperties))
+	for i, p := range snapshotProperties {
+		defaults[i] = p.get(ref)
+	}
+	e.tree(root, defaults, o.includeLayout)
+	return e.finish(w)
+}
+
+// DecodeTree reads a binary snapshot and builds a new tree from it like
+// TreeSnapshot.Build, setting the styles directly on the nodes
+func DecodeTree(r io.Reader) (*Node, error) {
+	d, c, err := decodeSnapshotHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	config := c.newConfig()
+	root := NewNodeWithConfig(config)
+	d.build(root, config)
+	if d.err == nil && len(d.data) != 0 {
+		d.err = errors.New("trailing data")
+	}
+	if d.err != nil {
+		root.FreeRecursive()
+		return nil, fmt.Errorf("invalid snapshot: %w", d.err)
+	}
+	return root, nil
+}
+
+// Encode writes the snapshot in the binary snapshot format
+func (s *TreeSnapshot) Encode(w io.Writer) error {
+	if s == nil || s.Root == nil {
+		return errors.New("encode of empty snapshot")
+	}
+	e := &snapshotEncoder{}
+	e.header(s.Config, s.Root.Layout != nil)
+	if err := e.node(s.Root); err != nil {
+		return err
+	}
+	return e.finish(w)
+}
+
+type snapshotEncoder struct {
+	buf     bytes.Buffer
+	payload bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+// header writes the header and the config
+func (e *snapshotEncoder) header(c ConfigSnapshot, includeLayout bool) {
+	e.buf.WriteString(snapshotMagic)
+	e.buf.WriteByte(snapshotMajorVersion)
+	e.buf.WriteByte(snapshotMinorVersion)
+	var flags uint64
+	if includeLayout {
+		flags |= snapshotFlagLayout
+	}
+	e.uvarint(&e.buf, flags)
+
+	e.float32Field(configTagPointScaleFactor, c.PointScaleFactor)
+	e.uvarintField(configTagErrata, uint64(c.Errata))
+	if c.UseWebDefaults {
+		e.uvarintField(configTagUseWebDefaults, 1)
+	}
+	for _, feature := range c.ExperimentalFeatures {
+		e.uvarintField(configTagExperimentalFeature, uint64(feature))
+	}
+	e.uvarint(&e.buf, 0)
+}
+
+// finish appends the checksum and writes the snapshot to w
+func (e *snapshotEncoder) finish(w io.Writer) error {
+	e.buf.Write(binary.LittleEndian.AppendUint32(e.scratch[:0], crc32.Checksum(e.buf.Bytes(), snapshotCRCTable)))
+	_, err := w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *snapshotEncoder) uvarint(b *bytes.Buffer, v uint64) {
+	b.Write(binary.AppendUvarint(e.scratch[:0], v))
+}
+
+func (e *snapshotEncoder) float32(b *bytes.Buffer, f float32) {
+	b.Write(binary.LittleEndian.AppendUint32(e.scratch[:0], math.Float32bits(f)))
+}
+
+// field writes the pending payload as a field with the given tag
+func (e *snapshotEncoder) field(tag uint64) {
+	e.uvarint(&e.buf, tag)
+	e.uvarint(&e.buf, uint64(e.payload.Len()))
+	e.buf.Write(e.payload.Bytes())
+	e.payload.Reset()
+}
+
+func (e *snapshotEncoder) uvarintField(tag, v uint64) {
+	e.uvarint(&e.payload, v)
+	e.field(tag)
+}
+
+func (e *snapshotEncoder) float32Field(tag uint64, f float32) {
+	e.float32(&e.payload, f)
+	e.field(tag)
+}
+
+func (e *snapshotEncoder) stringField(tag uint64, s string) {
+	e.payload.WriteString(s)
+	e.field(tag)
+}
+
+func (e *snapshotEncoder) styleField(code uint64, v styleValue) {
+	e.uvarint(&e.payload, code)
+	e.payload.WriteByte(v.kind)
+	switch v.kind {
+	case styleKindNumber, styleKindPercent:
+		e.float32(&e.payload, v.num)
+	case styleKindEnum:
+		e.uvarint(&e.payload, v.enum)
+	}
+	e.field(nodeTagStyle)
+}
+
+func (e *snapshotEncoder) layoutField(l Layout) {
+	for _, f := range []float32{l.Left, l.Right, l.Top, l.Bottom, l.Width, l.Height} {
+		e.float32(&e.payload, f)
+	}
+	e.field(nodeTagLayout)
+}
+
+// tree writes n and its descendants, with the style properties whose values
+// differ from defaults
+func (e *snapshotEncoder) tree(n *Node, defaults []styleValue, includeLayout bool) {
+	if id := n.GetID(); id != "" {
+		e.stringField(nodeTagID, id)
+	}
+	if attrs := getNodeAttrs(n.node, false); attrs != nil {
+		for _, class := range attrs.classes {
+			e.stringField(nodeTagClass, class)
+		}
+	}
+	for i, p := range snapshotProperties {
+		if v := p.get(n); v != defaults[i] {
+			e.styleField(uint64(i), v)
+		}
+	}
+	if includeLayout {
+		e.layoutField(n.GetComputedLayout())
+	}
+	e.uvarint(&e.buf, 0)
+
+	count := n.GetChildCount()
+	e.uvarint(&e.buf, uint64(count))
+	for i := uint32(0); i < count; i++ {
+		e.tree(n.GetChild(i), defaults, includeLayout)
+	}
+}
+
+func (e *snapshotEncoder) node(n *NodeSnapshot) error {
+	if n.ID != "" {
+		e.stringField(nodeTagID, n.ID)
+	}
+	for _, class := range n.Classes {
+		e.stringField(nodeTagClass, class)
+	}
+	for _, decl := range n.Style {
+		code, ok := snapshotPropertyCodes[decl.Property]
+		if !ok {
+			return fmt.Errorf("style property %s cannot be encoded", decl.Property)
+		}
+		v, err := snapshotProperties[code].parse(decl.Value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", decl.Property, err)
+		}
+		e.styleField(code, v)
+	}
+	if n.Layout != nil {
+		e.layoutField(*n.Layout)
+	}
+	e.uvarint(&e.buf, 0)
+
+	e.uvarint(&e.buf, uint64(len(n.Children)))
+	for _, child := range n.Children {
+		if err := e.node(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrSnapshotChecksum is returned when a binary snapshot fails its checksum
+var ErrSnapshotChecksum = errors.New("snapshot checksum mismatch")
+
+// DecodeSnapshot reads a binary snapshot without building nodes, e.g. for
+// read-only viewers of snapshots encoded with IncludeLayout
+func DecodeSnapshot(r io.Reader) (*TreeSnapshot, error) {
+	d, c, err := decodeSnapshotHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &TreeSnapshot{Config: c}
+	s.Root = d.node()
+	if d.err == nil && len(d.data) != 0 {
+		d.err = errors.New("trailing data")
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", d.err)
+	}
+	return s, nil
+}
+
+// decodeSnapshotHeader checks the snapshot and reads its header and config,
+// the returned decoder is positioned at the root node
+func decodeSnapshotHeader(r io.Reader) (*snapshotDecoder, ConfigSnapshot, error) {
+	var c ConfigSnapshot
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, c, err
+	}
+	if len(data) < len(snapshotMagic)+2+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, c, errors.New("not a yoga snapshot")
+	}
+	body, trailer := data[:len(data)-4], data[len(data)-4:]
+	if crc32.Checksum(body, snapshotCRCTable) != binary.LittleEndian.Uint32(trailer) {
+		return nil, c, ErrSnapshotChecksum
+	}
+	if major := body[len(snapshotMagic)]; major != snapshotMajorVersion {
+		return nil, c, fmt.Errorf("unsupported snapshot version %d", major)
+	}
+
+	d := &snapshotDecoder{data: body[len(snapshotMagic)+2:]}
+	d.uvarint() // flags, layouts are recognised by their field
+	for d.err == nil {
+		tag, payload := d.field()
+		if tag == 0 {
+			break
+		}
+		p := &snapshotDecoder{data: payload}
+		switch tag {
+		case configTagPointScaleFactor:
+			c.PointScaleFactor = p.float32()
+		case configTagErrata:
+			c.Errata = Errata(p.uvarint())
+		case configTagUseWebDefaults:
+			c.UseWebDefaults = p.uvarint() != 0
+		case configTagExperimentalFeature:
+			c.ExperimentalFeatures = append(c.ExperimentalFeatures, ExperimentalFeature(p.uvarint()))
+		}
+		d.setErr(p.err)
+	}
+	if d.err != nil {
+		return nil, c, fmt.Errorf("invalid snapshot: %w", d.err)
+	}
+	return d, c, nil
+}
+
+type snapshotDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *snapshotDecoder) setErr(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *snapshotDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *snapshotDecoder) bytes(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.data)) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *snapshotDecoder) float32() float32 {
+	b := d.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(b))
+}
+
+// field reads a field, returning tag 0 at the end of a field list
+func (d *snapshotDecoder) field() (uint64, []byte) {
+	tag := d.uvarint()
+	if tag == 0 || d.err != nil {
+		return 0, nil
+	}
+	return tag, d.bytes(d.uvarint())
+}
+
+// childCount reads the number of children following the fields of a node
+func (d *snapshotDecoder) childCount() uint64 {
+	count := d.uvarint()
+	// 每个子节点至少占两个字节，防止恶意的数量导致超大分配
+	if count > uint64(len(d.data))/2 {
+		d.setErr(errors.New("child count exceeds snapshot size"))
+	}
+	if d.err != nil {
+		return 0
+	}
+	return count
+}
+
+func (d *snapshotDecoder) node() *NodeSnapshot {
+	n := &NodeSnapshot{}
+	for d.err == nil {
+		tag, payload := d.field()
+		if tag == 0 {
+			break
+		}
+		p := &snapshotDecoder{data: payload}
+		switch tag {
+		case nodeTagID:
+			n.ID = string(payload)
+		case nodeTagClass:
+			n.Classes = append(n.Classes, string(payload))
+		case nodeTagStyle:
+			if prop, v, ok := p.style(); ok {
+				n.Style = append(n.Style, Declaration{Property: prop.name, Value: prop.format(v)})
+			}
+		case nodeTagLayout:
+			n.Layout = &Layout{
+				Left:   p.float32(),
+				Right:  p.float32(),
+				Top:    p.float32(),
+				Bottom: p.float32(),
+				Width:  p.float32(),
+				Height: p.float32(),
+			}
+		}
+		d.setErr(p.err)
+	}
+
+	count := d.childCount()
+	if d.err != nil {
+		return nil
+	}
+	if count > 0 {
+		n.Children = make([]*NodeSnapshot, 0, count)
+	}
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		n.Children = append(n.Children, d.node())
+	}
+	return n
+}
+
+// build sets the fields of a node on n and builds its children, layouts
+// are skipped
+func (d *snapshotDecoder) build(n *Node, config *Config) {
+	for d.err == nil {
+		tag, payload := d.field()
+		if tag == 0 {
+			break
+		}
+		p := &snapshotDecoder{data: payload}
+		switch tag {
+		case nodeTagID:
+			n.SetID(string(payload))
+		case nodeTagClass:
+			n.AddClass(string(payload))
+		case nodeTagStyle:
+			if prop, v, ok := p.style(); ok {
+				d.setErr(prop.set(n, v))
+			}
+		}
+		d.setErr(p.err)
+	}
+
+	count := d.childCount()
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		c := newChildNode(config)
+		n.InsertChild(c, uint32(i))
+		d.build(c, config)
+	}
+}
+
+// style decodes a style field; ok is false for properties and value kinds
+// added by newer versions of the format
+func (d *snapshotDecoder) style() (*snapshotProperty, styleValue, bool) {
+	code := d.uvarint()
+	kind := d.bytes(1)
+	if d.err != nil || code >= uint64(len(snapshotProperties)) {
+		return nil, styleValue{}, false
+	}
+	p := &snapshotProperties[code]
+	v := styleValue{kind: kind[0]}
+	switch v.kind {
+	case styleKindNumber, styleKindPercent:
+		v.num = d.float32()
+	case styleKindEnum:
+		v.enum = d.uvarint()
+	case styleKindUndefined, styleKindAuto, styleKindMaxContent, styleKindFitContent, styleKindStretch:
+	default:
+		return nil, styleValue{}, false
+	}
+	return p, v, d.err == nil
+}
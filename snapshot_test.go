@@ -0,0 +1,302 @@
+package yoga
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+// buildSnapshotTree creates a root with rows x cols children whose lifetime
+// is owned by the root
+func buildSnapshotTree(config *Config, rows, cols int) *Node {
+	root := NewNodeWithConfig(config)
+	root.SetWidth(1000)
+	root.SetPadding(EdgeAll, 4)
+	for i := 0; i < rows; i++ {
+		row := newChildNode(config)
+		row.SetFlexDirection(FlexDirectionRow)
+		row.SetGap(GutterColumn, 2)
+		row.AddClass("row")
+		for j := 0; j < cols; j++ {
+			cell := newChildNode(config)
+			cell.SetFlexGrow(1)
+			cell.SetHeightPercent(10)
+			cell.SetMarginAuto(EdgeStart)
+			cell.SetMinWidthMaxContent()
+			row.InsertChild(cell, uint32(j))
+		}
+		root.InsertChild(row, uint32(i))
+	}
+	return root
+}
+
+func TestEncodeDecodeTree(t *testing.T) {
+	r := assert.New(t)
+	config := NewConfig()
+	config.SetPointScaleFactor(2)
+	config.SetExperimentalFeatureEnabled(ExperimentalFeatureWebFlexBasis, true)
+
+	root := buildSnapshotTree(config, 3, 4)
+	defer root.FreeRecursive()
+	root.SetID("root")
+	root.SetBorder(EdgeLeft, 1.5)
+	root.GetChild(1).SetOverflow(OverflowScroll)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	var buf bytes.Buffer
+	r.Equal(nil, EncodeTree(&buf, root))
+	decoded, err := DecodeTree(bytes.NewReader(buf.Bytes()))
+	r.Equal(nil, err)
+	defer decoded.FreeRecursive()
+
+	r.Equal("root", decoded.GetID())
+	r.Equal(float32(2), decoded.GetConfig().PointScaleFactor())
+	r.True(decoded.GetConfig().IsExperimentalFeatureEnabled(ExperimentalFeatureWebFlexBasis))
+	r.Equal(float32(1.5), decoded.GetBorder(EdgeLeft))
+	r.Equal(uint32(3), decoded.GetChildCount())
+	r.Equal(OverflowScroll, decoded.GetChild(1).GetOverflow())
+	r.True(decoded.GetChild(2).HasClass("row"))
+
+	cell := decoded.GetChild(2).GetChild(3)
+	r.Equal(Value{Value: 10, Unit: UnitPercent}, cell.GetHeight())
+	r.Equal(UnitAuto, cell.GetMargin(EdgeStart).Unit)
+	r.Equal(UnitMaxContent, cell.GetMinWidth().Unit)
+
+	decoded.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	r.Equal(root.GetChild(2).GetChild(3).GetComputedLayout(), cell.GetComputedLayout())
+}
+
+func TestDecodeSnapshotLayout(t *testing.T) {
+	r := assert.New(t)
+	config := NewConfig()
+	root := buildSnapshotTree(config, 2, 2)
+	defer root.FreeRecursive()
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	var buf bytes.Buffer
+	r.Equal(nil, EncodeTree(&buf, root, IncludeLayout()))
+	s, err := DecodeSnapshot(&buf)
+	r.Equal(nil, err)
+	r.Equal(root.GetChild(1).GetChild(1).GetComputedLayout(), *s.Root.Children[1].Children[1].Layout)
+}
+
+func TestSnapshotEncodings(t *testing.T) {
+	r := assert.New(t)
+	config := NewConfig()
+	root := buildSnapshotTree(config, 2, 3)
+	defer root.FreeRecursive()
+	root.SetFlexWrap(WrapNoWrap)
+	root.SetFlex(2)
+	root.GetChild(0).SetAlignSelf(AlignBaseline)
+	root.GetChild(1).SetPositionAuto(EdgeTop)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+
+	// 直接从节点编码与经过 TreeSnapshot 编码的结果相同
+	var direct, viaSnapshot bytes.Buffer
+	r.Equal(nil, EncodeTree(&direct, root, IncludeLayout()))
+	s := NewTreeSnapshot(root, true)
+	r.Equal(nil, s.Encode(&viaSnapshot))
+	r.Equal(viaSnapshot.Bytes(), direct.Bytes())
+
+	decoded, err := DecodeSnapshot(&direct)
+	r.Equal(nil, err)
+	r.Equal(s.Root.Style, decoded.Root.Style)
+	r.Equal(s.Root.Children[0].Style, decoded.Root.Children[0].Style)
+	r.Equal(s.Root.Children[1].Children[2].Style, decoded.Root.Children[1].Children[2].Style)
+}
+
+func TestDecodeSnapshotErrors(t *testing.T) {
+	config := NewConfig()
+	root := buildSnapshotTree(config, 1, 1)
+	defer root.FreeRecursive()
+
+	var buf bytes.Buffer
+	if err := EncodeTree(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(corrupted)/2] ^= 0xff
+	if _, err := DecodeSnapshot(bytes.NewReader(corrupted)); err != ErrSnapshotChecksum {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+	if _, err := DecodeSnapshot(bytes.NewReader(data[:10])); err == nil {
+		t.Error("expected error for truncated snapshot")
+	}
+	if _, err := DecodeSnapshot(bytes.NewReader([]byte("not a snapshot at all"))); err == nil {
+		t.Error("expected error for bad magic")
+	}
+}
+
+func TestDecodeSnapshotSkipsUnknownFields(t *testing.T) {
+	// a snapshot from a newer minor version with an unknown config field,
+	// an unknown node field and an unknown style property
+	e := &snapshotEncoder{}
+	e.buf.WriteString(snapshotMagic)
+	e.buf.WriteByte(snapshotMajorVersion)
+	e.buf.WriteByte(snapshotMinorVersion + 1)
+	e.uvarint(&e.buf, 0)
+	e.float32Field(configTagPointScaleFactor, 1)
+	e.stringField(99, "from the future")
+	e.uvarint(&e.buf, 0)
+	e.stringField(nodeTagID, "a")
+	e.stringField(99, "from the future")
+	e.uvarint(&e.payload, uint64(len(snapshotProperties)+10))
+	e.payload.WriteByte(styleKindNumber)
+	e.float32(&e.payload, 1)
+	e.field(nodeTagStyle)
+	e.uvarint(&e.buf, 0)
+	e.uvarint(&e.buf, 0)
+	data := binary.LittleEndian.AppendUint32(e.buf.Bytes(), crc32.Checksum(e.buf.Bytes(), snapshotCRCTable))
+
+	decoded, err := DecodeSnapshot(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Root.ID != "a" || len(decoded.Root.Style) != 0 {
+		t.Errorf("unexpected decoded snapshot: %+v", decoded.Root)
+	}
+}
+
+func benchmarkSnapshot(b *testing.B) *TreeSnapshot {
+	config := NewConfig()
+	root := buildSnapshotTree(config, 100, 100)
+	b.Cleanup(root.FreeRecursive)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	return NewTreeSnapshot(root, true)
+}
+
+func BenchmarkSnapshotEncodeBinary(b *testing.B) {
+	s := benchmarkSnapshot(b)
+	var buf bytes.Buffer
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := s.Encode(&buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(buf.Len()), "bytes")
+}
+
+func BenchmarkSnapshotEncodeJSON(b *testing.B) {
+	s := benchmarkSnapshot(b)
+	var buf bytes.Buffer
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := json.NewEncoder(&buf).Encode(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(buf.Len()), "bytes")
+}
+
+func BenchmarkSnapshotDecodeBinary(b *testing.B) {
+	s := benchmarkSnapshot(b)
+	var buf bytes.Buffer
+	if err := s.Encode(&buf); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeSnapshot(bytes.NewReader(buf.Bytes())); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSnapshotDecodeJSON(b *testing.B) {
+	s := benchmarkSnapshot(b)
+	data, err := json.Marshal(s)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded TreeSnapshot
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 十万个节点的树上比较二进制格式与 JSON 的完整编解码过程
+func benchmarkTree(b *testing.B) *Node {
+	root := buildSnapshotTree(NewConfig(), 100, 1000)
+	b.Cleanup(root.FreeRecursive)
+	return root
+}
+
+func BenchmarkEncodeTree(b *testing.B) {
+	root := benchmarkTree(b)
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := EncodeTree(&buf, root); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(buf.Len()), "bytes")
+}
+
+func BenchmarkEncodeTreeJSON(b *testing.B) {
+	root := benchmarkTree(b)
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := json.NewEncoder(&buf).Encode(NewTreeSnapshot(root, false)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(buf.Len()), "bytes")
+}
+
+func BenchmarkDecodeTree(b *testing.B) {
+	var buf bytes.Buffer
+	if err := EncodeTree(&buf, benchmarkTree(b)); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root, err := DecodeTree(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		root.FreeRecursive()
+		b.StartTimer()
+	}
+}
+
+func BenchmarkDecodeTreeJSON(b *testing.B) {
+	data, err := json.Marshal(NewTreeSnapshot(benchmarkTree(b), false))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var s TreeSnapshot
+		if err := json.Unmarshal(data, &s); err != nil {
+			b.Fatal(err)
+		}
+		root, err := s.Build()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		root.FreeRecursive()
+		b.StartTimer()
+	}
+}
@@ -0,0 +1,433 @@
+package yoga
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// styleSetter applies a textual style value to a node
+type styleSetter func(n *Node, value string) error
+
+// styleSetters maps CSS property names to the Node setters they compile to
+var styleSetters = map[string]styleSetter{
+	"direction":       enumSetter(DirectionFromString, (*Node).SetDirection),
+	"flex-direction":  enumSetter(FlexDirectionFromString, (*Node).SetFlexDirection),
+	"justify-content": enumSetter(JustifyFromString, (*Node).SetJustifyContent),
+	"align-content":   enumSetter(AlignFromString, (*Node).SetAlignContent),
+	"align-items":     enumSetter(AlignFromString, (*Node).SetAlignItems),
+	"align-self":      enumSetter(AlignFromString, (*Node).SetAlignSelf),
+	"position":        enumSetter(PositionTypeFromString, (*Node).SetPositionType),
+	"flex-wrap":       enumSetter(wrapFromCSS, (*Node).SetFlexWrap),
+	"overflow":        enumSetter(OverflowFromString, (*Node).SetOverflow),
+	"display":         enumSetter(DisplayFromString, (*Node).SetDisplay),
+	"box-sizing":      enumSetter(BoxSizingFromString, (*Node).SetBoxSizing),
+
+	"flex":         numberSetter((*Node).SetFlex),
+	"flex-grow":    numberSetter((*Node).SetFlexGrow),
+	"flex-shrink":  numberSetter((*Node).SetFlexShrink),
+	"aspect-ratio": numberSetter((*Node).SetAspectRatio),
+
+	"flex-basis": lengthStyle((*Node).flexBasisSetter),
+	"width":      lengthStyle((*Node).widthSetter),
+	"height":     lengthStyle((*Node).heightSetter),
+	"min-width":  lengthStyle((*Node).minWidthSetter),
+	"min-height": lengthStyle((*Node).minHeightSetter),
+	"max-width":  lengthStyle((*Node).maxWidthSetter),
+	"max-height": lengthStyle((*Node).maxHeightSetter),
+
+	"gap":        gapStyle(GutterAll),
+	"row-gap":    gapStyle(GutterRow),
+	"column-gap": gapStyle(GutterColumn),
+
+	"margin":       edgesStyle(marginSetter),
+	"padding":      edgesStyle(paddingSetter),
+	"border":       edgesStyle(borderSetter),
+	"border-width": edgesStyle(borderSetter),
+}
+
+// styleEdges lists the edge suffixes accepted by edge properties,
+// including Yoga's logical and combined edges
+var styleEdges = map[string]Edge{
+	"left":       EdgeLeft,
+	"top":        EdgeTop,
+	"right":      EdgeRight,
+	"bottom":     EdgeBottom,
+	"start":      EdgeStart,
+	"end":        EdgeEnd,
+	"horizontal": EdgeHorizontal,
+	"vertical":   EdgeVertical,
+}
+
+func init() {
+	for name, edge := range styleEdges {
+		styleSetters[edgePropertyName("position", edge)] = edgeStyle(positionSetter, edge)
+		styleSetters["margin-"+name] = edgeStyle(marginSetter, edge)
+		styleSetters["padding-"+name] = edgeStyle(paddingSetter, edge)
+		styleSetters["border-"+name] = edgeStyle(borderSetter, edge)
+		styleSetters["border-"+name+"-width"] = edgeStyle(borderSetter, edge)
+	}
+	styleSetters["inset"] = edgeStyle(positionSetter, EdgeAll)
+}
+
+// edgePropertyName returns the canonical property name of an edge of the
+// margin, padding, border or position property
+func edgePropertyName(property string, edge Edge) string {
+	switch property {
+	case "position":
+		switch edge {
+		case EdgeAll:
+			return "inset"
+		case EdgeHorizontal, EdgeVertical:
+			return "inset-" + edge.String()
+		}
+		return edge.String()
+	case "border":
+		if edge == EdgeAll {
+			return "border-width"
+		}
+		return "border-" + edge.String() + "-width"
+	}
+	if edge == EdgeAll {
+		return property
+	}
+	return property + "-" + edge.String()
+}
+
+// SetStyleProperty sets a style property from its CSS name and value,
+// e.g. SetStyleProperty("flex-direction", "row") or SetStyleProperty("width", "50%")
+func (n *Node) SetStyleProperty(name, value string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	setter, ok := styleSetters[name]
+	if !ok {
+		return fmt.Errorf("unknown style property: %s", name)
+	}
+	if err := setter(n, strings.TrimSpace(value)); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	return nil
+}
+
+// IsStyleProperty reports whether name is a style property understood by SetStyleProperty
+func IsStyleProperty(name string) bool {
+	_, ok := styleSetters[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// lengthSetter groups the setters of a length property by unit,
+// a nil entry means the unit is not supported by the property
+type lengthSetter struct {
+	point      func(float32)
+	percent    func(float32)
+	auto       func()
+	maxContent func()
+	fitContent func()
+	stretch    func()
+}
+
+// supports reports whether the property accepts values of unit u
+func (s lengthSetter) supports(u Unit) bool {
+	switch u {
+	case UnitUndefined, UnitPoint:
+		return s.point != nil
+	case UnitPercent:
+		return s.percent != nil
+	case UnitAuto:
+		return s.auto != nil
+	case UnitMaxContent:
+		return s.maxContent != nil
+	case UnitFitContent:
+		return s.fitContent != nil
+	case UnitStretch:
+		return s.stretch != nil
+	}
+	return false
+}
+
+func (s lengthSetter) apply(v Value) error {
+	if !s.supports(v.Unit) {
+		return fmt.Errorf("unsupported unit %s", v.Unit)
+	}
+	switch v.Unit {
+	case UnitUndefined:
+		s.point(Undefined)
+	case UnitPoint:
+		s.point(v.Value)
+	case UnitPercent:
+		s.percent(v.Value)
+	case UnitAuto:
+		s.auto()
+	case UnitMaxContent:
+		s.maxContent()
+	case UnitFitContent:
+		s.fitContent()
+	case UnitStretch:
+		s.stretch()
+	}
+	return nil
+}
+
+func lengthStyle(setters func(n *Node) lengthSetter) styleSetter {
+	return func(n *Node, value string) error {
+		v, err := ParseValue(value)
+		if err != nil {
+			return err
+		}
+		return setters(n).apply(v)
+	}
+}
+
+func (n *Node) flexBasisSetter() lengthSetter {
+	return lengthSetter{
+		point: n.SetFlexBasis, percent: n.SetFlexBasisPercent, auto: n.SetFlexBasisAuto,
+		maxContent: n.SetFlexBasisMaxContent, fitContent: n.SetFlexBasisFitContent, stretch: n.SetFlexBasisStretch,
+	}
+}
+
+func (n *Node) widthSetter() lengthSetter {
+	return lengthSetter{
+		point: n.SetWidth, percent: n.SetWidthPercent, auto: n.SetWidthAuto,
+		maxContent: n.SetWidthMaxContent, fitContent: n.SetWidthFitContent, stretch: n.SetWidthStretch,
+	}
+}
+
+func (n *Node) heightSetter() lengthSetter {
+	return lengthSetter{
+		point: n.SetHeight, percent: n.SetHeightPercent, auto: n.SetHeightAuto,
+		maxContent: n.SetHeightMaxContent, fitContent: n.SetHeightFitContent, stretch: n.SetHeightStretch,
+	}
+}
+
+func (n *Node) minWidthSetter() lengthSetter {
+	return lengthSetter{
+		point: n.SetMinWidth, percent: n.SetMinWidthPercent,
+		maxContent: n.SetMinWidthMaxContent, fitContent: n.SetMinWidthFitContent, stretch: n.SetMinWidthStretch,
+	}
+}
+
+func (n *Node) minHeightSetter() lengthSetter {
+	return lengthSetter{
+		point: n.SetMinHeight, percent: n.SetMinHeightPercent,
+		maxContent: n.SetMinHeightMaxContent, fitContent: n.SetMinHeightFitContent, stretch: n.SetMinHeightStretch,
+	}
+}
+
+func (n *Node) maxWidthSetter() lengthSetter {
+	return lengthSetter{
+		point: n.SetMaxWidth, percent: n.SetMaxWidthPercent,
+		maxContent: n.SetMaxWidthMaxContent, fitContent: n.SetMaxWidthFitContent, stretch: n.SetMaxWidthStretch,
+	}
+}
+
+func (n *Node) maxHeightSetter() lengthSetter {
+	return lengthSetter{
+		point: n.SetMaxHeight, percent: n.SetMaxHeightPercent,
+		maxContent: n.SetMaxHeightMaxContent, fitContent: n.SetMaxHeightFitContent, stretch: n.SetMaxHeightStretch,
+	}
+}
+
+func gapSetter(n *Node, gutter Gutter) lengthSetter {
+	return lengthSetter{
+		point:   func(v float32) { n.SetGap(gutter, v) },
+		percent: func(v float32) { n.SetGapPercent(gutter, v) },
+	}
+}
+
+func gapStyle(gutter Gutter) styleSetter {
+	return lengthStyle(func(n *Node) lengthSetter { return gapSetter(n, gutter) })
+}
+
+func marginSetter(n *Node, edge Edge) lengthSetter {
+	return lengthSetter{
+		point:   func(v float32) { n.SetMargin(edge, v) },
+		percent: func(v float32) { n.SetMarginPercent(edge, v) },
+		auto:    func() { n.SetMarginAuto(edge) },
+	}
+}
+
+func paddingSetter(n *Node, edge Edge) lengthSetter {
+	return lengthSetter{
+		point:   func(v float32) { n.SetPadding(edge, v) },
+		percent: func(v float32) { n.SetPaddingPercent(edge, v) },
+	}
+}
+
+func borderSetter(n *Node, edge Edge) lengthSetter {
+	return lengthSetter{
+		point: func(v float32) { n.SetBorder(edge, v) },
+	}
+}
+
+func positionSetter(n *Node, edge Edge) lengthSetter {
+	return lengthSetter{
+		point:   func(v float32) { n.SetPosition(edge, v) },
+		percent: func(v float32) { n.SetPositionPercent(edge, v) },
+		auto:    func() { n.SetPositionAuto(edge) },
+	}
+}
+
+func edgeStyle(setters func(n *Node, edge Edge) lengthSetter, edge Edge) styleSetter {
+	return lengthStyle(func(n *Node) lengthSetter { return setters(n, edge) })
+}
+
+// edgesStyle implements the CSS box shorthand with one to four values
+// in top, right, bottom, left order
+func edgesStyle(setters func(n *Node, edge Edge) lengthSetter) styleSetter {
+	return func(n *Node, value string) error {
+		fields := strings.Fields(value)
+		if len(fields) == 0 || len(fields) > 4 {
+			return fmt.Errorf("expected 1 to 4 values, got %q", value)
+		}
+		values := make([]Value, len(fields))
+		for i, field := range fields {
+			v, err := ParseValue(field)
+			if err != nil {
+				return err
+			}
+			values[i] = v
+		}
+		if len(values) == 1 {
+			return setters(n, EdgeAll).apply(values[0])
+		}
+		// top, right, bottom, left with CSS fallbacks for missing values
+		for len(values) < 4 {
+			values = append(values, values[len(values)-2])
+		}
+		edges := []Edge{EdgeTop, EdgeRight, EdgeBottom, EdgeLeft}
+		// 先检查所有的值，避免只设置了部分边
+		for i, edge := range edges {
+			if !setters(n, edge).supports(values[i].Unit) {
+				return fmt.Errorf("unsupported unit %s", values[i].Unit)
+			}
+		}
+		for i, edge := range edges {
+			if err := setters(n, edge).apply(values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func numberSetter(set func(n *Node, v float32)) styleSetter {
+	return func(n *Node, value string) error {
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return err
+		}
+		set(n, float32(f))
+		return nil
+	}
+}
+
+func enumSetter[T any](parse func(string) (T, error), set func(n *Node, v T)) styleSetter {
+	return func(n *Node, value string) error {
+		v, err := parse(value)
+		if err != nil {
+			return err
+		}
+		set(n, v)
+		return nil
+	}
+}
+
+// wrapFromCSS accepts the CSS spelling "nowrap" in addition to Yoga's "no-wrap"
+func wrapFromCSS(s string) (Wrap, error) {
+	if s == "nowrap" {
+		return WrapNoWrap, nil
+	}
+	return WrapFromString(s)
+}
+
+// ParseDeclarations parses an inline style such as "width: 100px; flex-grow: 1",
+// rejecting properties SetStyleProperty does not understand
+func ParseDeclarations(src string) ([]Declaration, error) {
+	var decls []Declaration
+	for _, part := range strings.Split(src, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid declaration: %s", part)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if !IsStyleProperty(name) {
+			return nil, fmt.Errorf("unknown style property: %s", name)
+		}
+		decls = append(decls, Declaration{Property: name, Value: value})
+	}
+	return decls, nil
+}
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
+
+// allEdges lists every edge in the order style properties are reported
+var allEdges = []Edge{EdgeAll, EdgeHorizontal, EdgeVertical, EdgeLeft, EdgeTop, EdgeRight, EdgeBottom, EdgeStart, EdgeEnd}
+
+// styleDeclarations lists the style properties of n whose values differ from
+// those of ref, typically a fresh node created with the same config, in a
+// stable order. The result can be fed back to SetStyleProperty.
+func styleDeclarations(n, ref *Node) []Declaration {
+	var decls []Declaration
+	add := func(property, value, refValue string) {
+		if value != refValue {
+			decls = append(decls, Declaration{Property: property, Value: value})
+		}
+	}
+
+	add("direction", n.GetDirection().String(), ref.GetDirection().String())
+	add("flex-direction", n.GetFlexDirection().String(), ref.GetFlexDirection().String())
+	add("justify-content", n.GetJustifyContent().String(), ref.GetJustifyContent().String())
+	add("align-content", n.GetAlignContent().String(), ref.GetAlignContent().String())
+	add("align-items", n.GetAlignItems().String(), ref.GetAlignItems().String())
+	add("align-self", n.GetAlignSelf().String(), ref.GetAlignSelf().String())
+	add("position", n.GetPositionType().String(), ref.GetPositionType().String())
+	add("flex-wrap", wrapToCSS(n.GetFlexWrap()), wrapToCSS(ref.GetFlexWrap()))
+	add("overflow", n.GetOverflow().String(), ref.GetOverflow().String())
+	add("display", n.GetDisplay().String(), ref.GetDisplay().String())
+	add("box-sizing", n.GetBoxSizing().String(), ref.GetBoxSizing().String())
+
+	add("flex", formatFloat(n.GetFlex()), formatFloat(ref.GetFlex()))
+	add("flex-grow", formatFloat(n.GetFlexGrow()), formatFloat(ref.GetFlexGrow()))
+	add("flex-shrink", formatFloat(n.GetFlexShrink()), formatFloat(ref.GetFlexShrink()))
+	add("aspect-ratio", formatFloat(n.GetAspectRatio()), formatFloat(ref.GetAspectRatio()))
+
+	add("flex-basis", n.GetFlexBasis().String(), ref.GetFlexBasis().String())
+	add("width", n.GetWidth().String(), ref.GetWidth().String())
+	add("height", n.GetHeight().String(), ref.GetHeight().String())
+	add("min-width", n.GetMinWidth().String(), ref.GetMinWidth().String())
+	add("min-height", n.GetMinHeight().String(), ref.GetMinHeight().String())
+	add("max-width", n.GetMaxWidth().String(), ref.GetMaxWidth().String())
+	add("max-height", n.GetMaxHeight().String(), ref.GetMaxHeight().String())
+
+	add("gap", n.GetGap(GutterAll).String(), ref.GetGap(GutterAll).String())
+	add("row-gap", n.GetGap(GutterRow).String(), ref.GetGap(GutterRow).String())
+	add("column-gap", n.GetGap(GutterColumn).String(), ref.GetGap(GutterColumn).String())
+
+	for _, edge := range allEdges {
+		add(edgePropertyName("margin", edge), n.GetMargin(edge).String(), ref.GetMargin(edge).String())
+	}
+	for _, edge := range allEdges {
+		add(edgePropertyName("padding", edge), n.GetPadding(edge).String(), ref.GetPadding(edge).String())
+	}
+	for _, edge := range allEdges {
+		add(edgePropertyName("border", edge), formatFloat(n.GetBorder(edge)), formatFloat(ref.GetBorder(edge)))
+	}
+	for _, edge := range allEdges {
+		add(edgePropertyName("position", edge), n.GetPosition(edge).String(), ref.GetPosition(edge).String())
+	}
+	return decls
+}
+
+// wrapToCSS returns the CSS spelling of a Wrap value
+func wrapToCSS(w Wrap) string {
+	if w == WrapNoWrap {
+		return "nowrap"
+	}
+	return w.String()
+}
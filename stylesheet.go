@@ -0,0 +1,204 @@
+package yoga
+
+/*
+#include "cgo_wrapper.h"
+*/
+import "C"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Declaration is a single "property: value" pair of a rule
+type Declaration struct {
+	Property string `json:"property"`
+	Value    string `json:"value"`
+}
+
+// Rule is a selector together with the declarations it applies
+type Rule struct {
+	Selector     string
+	Declarations []Declaration
+
+	selectors []complexSelector
+	order     int
+}
+
+// Stylesheet is an ordered set of rules that compile down to Node setters.
+//
+// Selectors support ids (#id), classes (.class), the universal selector (*),
+// descendant and child (>) combinators and the :first-child/:last-child
+// pseudo-classes. Property names follow CSS, see SetStyleProperty.
+type Stylesheet struct {
+	rules []*Rule
+}
+
+// NewStylesheet creates an empty stylesheet
+func NewStylesheet() *Stylesheet {
+	return &Stylesheet{}
+}
+
+// ParseStylesheet creates a stylesheet from CSS-like source such as
+// ".card { padding: 8; flex-direction: row }"
+func ParseStylesheet(src string) (*Stylesheet, error) {
+	s := NewStylesheet()
+	if err := s.Parse(src); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Parse appends the rules in src to the stylesheet
+func (s *Stylesheet) Parse(src string) error {
+	for {
+		src = strings.TrimSpace(src)
+		if src == "" {
+			return nil
+		}
+		open := strings.IndexByte(src, '{')
+		if open < 0 {
+			return fmt.Errorf("missing '{' after selector: %s", src)
+		}
+		end := strings.IndexByte(src[open:], '}')
+		if end < 0 {
+			return fmt.Errorf("missing '}' in rule: %s", src)
+		}
+		if _, err := s.AddRule(src[:open], src[open+1:open+end]); err != nil {
+			return err
+		}
+		src = src[open+end+1:]
+	}
+}
+
+// AddRule appends a rule with the given selector and declarations, e.g.
+// AddRule("#sidebar > .item", "flex-grow: 1; margin: 4")
+func (s *Stylesheet) AddRule(selector string, declarations string) (*Rule, error) {
+	selector = strings.TrimSpace(selector)
+	selectors, err := parseSelectorGroup(selector)
+	if err != nil {
+		return nil, err
+	}
+	decls, err := ParseDeclarations(declarations)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", selector, err)
+	}
+
+	rule := &Rule{
+		Selector:     selector,
+		Declarations: decls,
+		selectors:    selectors,
+		order:        len(s.rules),
+	}
+	// 在临时节点上验证取值，避免 Apply 时才发现错误
+	scratch := NewNode()
+	defer scratch.Destroy()
+	if err := rule.check(scratch); err != nil {
+		return nil, err
+	}
+	s.rules = append(s.rules, rule)
+	return rule, nil
+}
+
+// check sets the declarations of the rule on scratch to validate them
+func (r *Rule) check(scratch *Node) error {
+	for _, decl := range r.Declarations {
+		if err := scratch.SetStyleProperty(decl.Property, decl.Value); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Selector, err)
+		}
+	}
+	return nil
+}
+
+// Rules returns the rules in source order
+func (s *Stylesheet) Rules() []*Rule {
+	return append([]*Rule(nil), s.rules...)
+}
+
+// StyleReport records which rule set each property of each node
+type StyleReport struct {
+	sources map[C.YGNodeRef]map[string]*Rule
+}
+
+// Source returns the rule that set property on n, or nil if no rule did
+func (r *StyleReport) Source(n *Node, property string) *Rule {
+	if r == nil || n == nil {
+		return nil
+	}
+	return r.sources[n.node][strings.ToLower(property)]
+}
+
+// Properties returns the properties set on n, keyed by property name
+func (r *StyleReport) Properties(n *Node) map[string]*Rule {
+	if r == nil || n == nil {
+		return nil
+	}
+	props := make(map[string]*Rule, len(r.sources[n.node]))
+	for name, rule := range r.sources[n.node] {
+		props[name] = rule
+	}
+	return props
+}
+
+// ruleMatch is a rule matched against an element with its effective specificity
+type ruleMatch struct {
+	rule        *Rule
+	specificity int
+}
+
+// Apply matches the rules against root and its descendants and calls the
+// corresponding setters, ordered by specificity and then source order so
+// that later and more specific declarations win. Properties not mentioned
+// by any matching rule keep their current values. The declarations of all
+// rules are validated first, on an error no node is changed.
+func (s *Stylesheet) Apply(root *Node) (*StyleReport, error) {
+	// Rule.Declarations 可能在 AddRule 之后被修改，先全部检查一遍
+	scratch := NewNode()
+	defer scratch.Destroy()
+	for _, rule := range s.rules {
+		if err := rule.check(scratch); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &StyleReport{sources: make(map[C.YGNodeRef]map[string]*Rule)}
+	walkElements(root, func(e *element) {
+		matches := s.match(e)
+		if len(matches) == 0 {
+			return
+		}
+		sources := make(map[string]*Rule)
+		for _, m := range matches {
+			for _, decl := range m.rule.Declarations {
+				// 声明在上面已经检查过
+				_ = e.node.SetStyleProperty(decl.Property, decl.Value)
+				sources[decl.Property] = m.rule
+			}
+		}
+		report.sources[e.node.node] = sources
+	})
+	return report, nil
+}
+
+// match returns the rules matching e in cascade order
+func (s *Stylesheet) match(e *element) []ruleMatch {
+	var matches []ruleMatch
+	for _, rule := range s.rules {
+		best := -1
+		for _, sel := range rule.selectors {
+			if spec := sel.specificity(); spec > best && sel.matches(e) {
+				best = spec
+			}
+		}
+		if best >= 0 {
+			matches = append(matches, ruleMatch{rule: rule, specificity: best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].specificity != matches[j].specificity {
+			return matches[i].specificity < matches[j].specificity
+		}
+		return matches[i].rule.order < matches[j].rule.order
+	})
+	return matches
+}
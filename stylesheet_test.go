@@ -0,0 +1,118 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestStylesheetCascade(t *testing.T) {
+	r := assert.New(t)
+
+	sheet, err := ParseStylesheet(`
+		.item { height: 10; flex-grow: 1 }
+		#list > .item:first-child { height: 20 }
+		.item:last-child { margin: 5 10 }
+		#list .item { width: 50% }
+		.item { width: 30 }
+	`)
+	r.Equal(nil, err)
+
+	root := NewNode()
+	defer root.Destroy()
+	root.SetID("list")
+	root.SetWidth(200)
+
+	items := make([]*Node, 3)
+	for i := range items {
+		items[i] = NewNode()
+		defer items[i].Destroy()
+		items[i].AddClass("item")
+		root.InsertChild(items[i], uint32(i))
+	}
+
+	report, err := sheet.Apply(root)
+	r.Equal(nil, err)
+
+	r.Equal(Value{Value: 20, Unit: UnitPoint}, items[0].GetHeight())
+	r.Equal(Value{Value: 10, Unit: UnitPoint}, items[1].GetHeight())
+	r.Equal(float32(1), items[1].GetFlexGrow())
+
+	// "#list .item" is more specific than the later ".item" rule
+	r.Equal(Value{Value: 50, Unit: UnitPercent}, items[1].GetWidth())
+	r.Equal(Value{Value: 10, Unit: UnitPoint}, items[2].GetMargin(EdgeLeft))
+	r.Equal(Value{Value: 5, Unit: UnitPoint}, items[2].GetMargin(EdgeBottom))
+
+	r.Equal("#list > .item:first-child", report.Source(items[0], "height").Selector)
+	r.Equal(".item", report.Source(items[1], "height").Selector)
+	r.Equal("#list .item", report.Source(root.GetChild(1), "width").Selector)
+	r.True(report.Source(root, "width") == nil)
+	r.Equal(4, len(report.Properties(items[2])))
+}
+
+func TestStylesheetErrors(t *testing.T) {
+	sheet := NewStylesheet()
+	if _, err := sheet.AddRule(".a", "no-such-property: 1"); err == nil {
+		t.Error("expected error for unknown property")
+	}
+	if _, err := sheet.AddRule(".a", "width: wide"); err == nil {
+		t.Error("expected error for invalid value")
+	}
+	if _, err := sheet.AddRule(".a >", "width: 1"); err == nil {
+		t.Error("expected error for dangling combinator")
+	}
+	if _, err := sheet.AddRule("div", "width: 1"); err == nil {
+		t.Error("expected error for type selector")
+	}
+	if _, err := ParseStylesheet(".a { width: 1"); err == nil {
+		t.Error("expected error for unterminated rule")
+	}
+	if _, err := sheet.AddRule(".a", "padding: 1 auto"); err == nil {
+		t.Error("expected error for unsupported edge value")
+	}
+}
+
+func TestStylesheetApplyInvalid(t *testing.T) {
+	r := assert.New(t)
+	sheet, err := ParseStylesheet(".a { width: 10 } .a { height: 10 }")
+	r.Equal(nil, err)
+	root := NewNode()
+	defer root.Destroy()
+	root.AddClass("a")
+
+	// 规则在解析之后被改成了无效的声明
+	rule := sheet.Rules()[1]
+	rule.Declarations = append(rule.Declarations, Declaration{Property: "margin", Value: "1 auto 2 wide"})
+	report, err := sheet.Apply(root)
+	r.NotNil(err)
+	r.True(report == nil)
+	r.Equal(UnitAuto, root.GetWidth().Unit)
+	r.Equal(UnitAuto, root.GetHeight().Unit)
+	r.Equal(UnitUndefined, root.GetMargin(EdgeTop).Unit)
+
+	// 只设置了部分边的值也算错误，节点保持不变
+	rule.Declarations = []Declaration{{Property: "padding", Value: "1 auto"}}
+	_, err = sheet.Apply(root)
+	r.NotNil(err)
+	r.Equal(UnitAuto, root.GetWidth().Unit)
+	r.Equal(UnitUndefined, root.GetPadding(EdgeTop).Unit)
+}
+
+func TestNodeClasses(t *testing.T) {
+	r := assert.New(t)
+	node := NewNode()
+	defer node.Destroy()
+
+	node.SetClasses("a", "b", "a")
+	r.Equal([]string{"a", "b"}, node.GetClasses())
+	node.RemoveClass("a")
+	r.False(node.HasClass("a"))
+	r.True(node.HasClass("b"))
+
+	node.SetID("main")
+	clone := node.Clone()
+	defer clone.Destroy()
+	clone.AddClass("c")
+	r.Equal("main", clone.GetID())
+	r.False(node.HasClass("c"))
+}
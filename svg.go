@@ -0,0 +1,148 @@
+package yoga
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+// SVGOptions configures RenderSVG, the zero value renders at scale 1
+// without labels
+type SVGOptions struct {
+	// Scale multiplies every coordinate, 0 means 1
+	Scale float32
+	// Labels draws a label in the top left corner of every box
+	Labels bool
+	// Label returns the label of a node. By default nodes are labeled with
+	// their id, or their context when that is a string or a fmt.Stringer.
+	Label func(n *Node) string
+}
+
+// colors of the margin, border and padding bands, as in browser devtools
+const (
+	svgMarginFill   = "#f9cc9d"
+	svgBorderFill   = "#fddd9b"
+	svgPaddingFill  = "#c3d08b"
+	svgStroke       = "#333333"
+	svgOverflow     = "#dd3333"
+	svgRTLMarker    = "#1f5fbf"
+	svgLabelFill    = "#111111"
+	svgBandOpacity  = "0.6"
+	svgLabelSize    = 10
+	svgMarkerLength = 6
+)
+
+// RenderSVG draws the computed layout of root and its descendants as an
+// SVG document.
+//
+// Every node is drawn as its border box surrounded by its margin band, with
+// the border and padding bands inside. Nodes where HadOverflow is true are
+// outlined in red and nodes laid out right to left carry a small arrow in
+// their top right corner. Each box is a group with a data-node attribute
+// holding its index path, such as "0/2".
+func RenderSVG(w io.Writer, root *Node, opts *SVGOptions) error {
+	var o SVGOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Scale <= 0 {
+		o.Scale = 1
+	}
+	if o.Label == nil {
+		o.Label = defaultLabel
+	}
+
+	boxes := layoutBoxes(root)
+	var minX, minY, maxX, maxY float32
+	for _, box := range boxes {
+		m := edges(box.node.GetComputedMargin)
+		minX = min(minX, box.x-m[0])
+		minY = min(minY, box.y-m[1])
+		maxX = max(maxX, box.x+box.layout.Width+m[2])
+		maxY = max(maxY, box.y+box.layout.Height+m[3])
+	}
+	width, height := maxX-minX, maxY-minY
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"%s %s %s %s\">\n",
+		formatFloat(width*o.Scale), formatFloat(height*o.Scale),
+		formatFloat(minX), formatFloat(minY), formatFloat(width), formatFloat(height))
+	for _, box := range boxes {
+		renderSVGBox(bw, box, &o)
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func renderSVGBox(w *bufio.Writer, box *layoutBox, o *SVGOptions) {
+	n := box.node
+	path := box.path
+	if path == "" {
+		path = "root"
+	}
+	fmt.Fprintf(w, "  <g data-node=\"%s\">\n", path)
+
+	x, y, width, height := box.x, box.y, box.layout.Width, box.layout.Height
+	margin := edges(n.GetComputedMargin)
+	border := edges(n.GetComputedBorder)
+	padding := edges(n.GetComputedPadding)
+
+	// 由外向内：margin 带、border 带、padding 带
+	outer := [4]float32{x - margin[0], y - margin[1], width + margin[0] + margin[2], height + margin[1] + margin[3]}
+	borderBox := [4]float32{x, y, width, height}
+	paddingBox := insetRect(borderBox, border)
+	contentBox := insetRect(paddingBox, padding)
+	writeSVGBand(w, "margin", outer, borderBox, svgMarginFill)
+	writeSVGBand(w, "border", borderBox, paddingBox, svgBorderFill)
+	writeSVGBand(w, "padding", paddingBox, contentBox, svgPaddingFill)
+
+	stroke, strokeWidth, class := svgStroke, "1", "box"
+	if n.HadOverflow() {
+		stroke, strokeWidth, class = svgOverflow, "2", "box overflow"
+	}
+	fmt.Fprintf(w, "    <rect class=\"%s\" x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%s\" vector-effect=\"non-scaling-stroke\"/>\n",
+		class, formatFloat(x), formatFloat(y), formatFloat(width), formatFloat(height), stroke, strokeWidth)
+
+	if n.GetLayoutDirection() == DirectionRTL && width >= svgMarkerLength+4 && height >= svgMarkerLength+4 {
+		// 右上角指向左侧的箭头
+		tipX, tipY := x+width-2-svgMarkerLength, y+2+svgMarkerLength/2
+		fmt.Fprintf(w, "    <path class=\"rtl\" d=\"M%s %s L%s %s L%s %s Z\" fill=\"%s\"/>\n",
+			formatFloat(tipX), formatFloat(tipY),
+			formatFloat(x+width-2), formatFloat(y+2),
+			formatFloat(x+width-2), formatFloat(y+2+svgMarkerLength), svgRTLMarker)
+	}
+
+	if o.Labels {
+		if label := o.Label(n); label != "" {
+			fmt.Fprintf(w, "    <text x=\"%s\" y=\"%s\" font-family=\"monospace\" font-size=\"%d\" fill=\"%s\">%s</text>\n",
+				formatFloat(x+2), formatFloat(y+svgLabelSize), svgLabelSize, svgLabelFill, html.EscapeString(label))
+		}
+	}
+	w.WriteString("  </g>\n")
+}
+
+// insetRect shrinks an x, y, width, height rect by left, top, right, bottom
+// insets, never below an empty rect
+func insetRect(r [4]float32, inset [4]float32) [4]float32 {
+	return [4]float32{
+		r[0] + inset[0],
+		r[1] + inset[1],
+		max(0, r[2]-inset[0]-inset[2]),
+		max(0, r[3]-inset[1]-inset[3]),
+	}
+}
+
+// writeSVGBand fills the area between outer and inner, skipping empty bands
+func writeSVGBand(w *bufio.Writer, class string, outer, inner [4]float32, fill string) {
+	if outer == inner {
+		return
+	}
+	fmt.Fprintf(w, "    <path class=\"%s\" d=\"%s%s\" fill=\"%s\" fill-opacity=\"%s\" fill-rule=\"evenodd\"/>\n",
+		class, svgRectPath(outer), svgRectPath(inner), fill, svgBandOpacity)
+}
+
+func svgRectPath(r [4]float32) string {
+	return fmt.Sprintf("M%s %sh%sv%sh%sZ",
+		formatFloat(r[0]), formatFloat(r[1]), formatFloat(r[2]), formatFloat(r[3]), formatFloat(-r[2]))
+}
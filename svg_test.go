@@ -0,0 +1,73 @@
+package yoga
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRenderSVG(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	defer root.FreeRecursive()
+	root.SetFlexDirection(FlexDirectionRow)
+	root.SetWidth(100)
+	root.SetHeight(40)
+	root.SetPadding(EdgeAll, 4)
+	root.SetBorder(EdgeAll, 1)
+
+	child := newChildNode(config)
+	child.SetID("a&b")
+	child.SetMargin(EdgeLeft, 2)
+	child.SetWidth(30)
+	root.InsertChild(child, 0)
+
+	wide := newChildNode(config)
+	wide.SetWidth(200)
+	wide.SetFlexShrink(0)
+	wide.SetContext("wide<")
+	root.InsertChild(wide, 1)
+
+	hidden := newChildNode(config)
+	hidden.SetDisplay(DisplayNone)
+	root.InsertChild(hidden, 2)
+
+	root.CalculateLayout(Undefined, Undefined, DirectionRTL)
+
+	var buf bytes.Buffer
+	if err := RenderSVG(&buf, root, &SVGOptions{Scale: 2, Labels: true}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	// well-formed XML
+	d := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, out)
+		}
+	}
+
+	for _, want := range []string{
+		`width="474" height="80" viewBox="-137 0 237 40"`,
+		`<g data-node="root">`,
+		`<g data-node="0">`,
+		`<path class="margin" d="M63 5h32v30h-32ZM65 5h30v30h-30Z"`,
+		`<path class="padding" d="M1 1h98v38h-98ZM5 5h90v30h-90Z"`,
+		`class="box overflow"`,
+		`<path class="rtl"`,
+		`>a&amp;b</text>`,
+		`>wide&lt;</text>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("SVG is missing %s:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `data-node="2"`) {
+		t.Error("display none nodes should not be drawn")
+	}
+}
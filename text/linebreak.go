@@ -0,0 +1,136 @@
+package text
+
+import "unicode"
+
+// breakClass is a reduced set of the UAX #14 line breaking classes
+type breakClass int
+
+const (
+	classAL breakClass = iota // letters, digits and everything else
+	classBK                   // mandatory break after
+	classCR
+	classLF
+	classSP // breakable space
+	classGL // glue, e.g. no-break space
+	classZW // zero width space
+	classBA // break after, e.g. hyphens
+	classHY // hyphen-minus
+	classCL // no break before: closing punctuation, commas, exclamation marks
+	classOP // no break after: opening punctuation
+	classID // ideographs, break before and after
+	classCM // combining marks, attach to the previous character
+)
+
+func classify(r rune) breakClass {
+	switch r {
+	case '\n':
+		return classLF
+	case '\r':
+		return classCR
+	case '\v', '\f', 0x85, 0x2028, 0x2029:
+		return classBK
+	case ' ', '\t':
+		return classSP
+	case 0xa0, 0x202f, 0x2007, 0x2060, 0xfeff:
+		return classGL
+	case 0x200b:
+		return classZW
+	case '-':
+		return classHY
+	case 0xad, 0x2010, 0x2012, 0x2013, '|':
+		return classBA
+	case ')', ']', '}', ',', '.', '!', '?', ';', ':', '/', '%',
+		'、', '。', '，', '．', '：', '；', '！', '？', '）', '」', '』', '】', '〉', '》', '〕', 'ー', 'ゝ', 'ゞ', '々':
+		return classCL
+	case '(', '[', '{', '（', '「', '『', '【', '〈', '《', '〔':
+		return classOP
+	}
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me), r == 0x200d, r >= 0xfe00 && r <= 0xfe0f:
+		return classCM
+	case r >= 0x2e80 && r <= 0x9fff, r >= 0xac00 && r <= 0xd7af, r >= 0xf900 && r <= 0xfaff,
+		r >= 0xff01 && r <= 0xff60, r >= 0x20000 && r <= 0x3fffd:
+		return classID
+	}
+	return classAL
+}
+
+// breakAction is the line break opportunity before a character
+type breakAction int
+
+const (
+	breakProhibited breakAction = iota
+	breakAllowed
+	breakMandatory
+)
+
+// breakActions returns the break opportunity before each rune of runes, the
+// first entry is always breakProhibited. It implements the pair rules of
+// UAX #14 for the classes above: mandatory breaks after BK, CR and LF,
+// no break before spaces and closing punctuation, no break after opening
+// punctuation even across spaces, no break around glue, breaks after spaces,
+// hyphens and zero width spaces and around ideographs.
+func breakActions(runes []rune) []breakAction {
+	actions := make([]breakAction, len(runes))
+	if len(runes) == 0 {
+		return actions
+	}
+	// prev 是跳过组合字符后的前一个字符类别，beforeSpaces 是空格之前的类别
+	prev := classify(runes[0])
+	if prev == classCM {
+		prev = classAL
+	}
+	beforeSpaces := prev
+	for i := 1; i < len(runes); i++ {
+		cur := classify(runes[i])
+		actions[i] = pairAction(prev, beforeSpaces, cur, runes[i])
+		if cur == classCM && prev != classBK && prev != classCR && prev != classLF && prev != classSP && prev != classZW {
+			// LB9：组合字符继承前一个字符的类别
+			continue
+		}
+		if cur == classCM {
+			cur = classAL
+		}
+		if cur != classSP {
+			beforeSpaces = cur
+		}
+		prev = cur
+	}
+	return actions
+}
+
+func pairAction(prev, beforeSpaces, cur breakClass, r rune) breakAction {
+	switch {
+	case prev == classBK, prev == classLF:
+		return breakMandatory
+	case prev == classCR:
+		if cur == classLF {
+			return breakProhibited
+		}
+		return breakMandatory
+	case cur == classBK, cur == classCR, cur == classLF, cur == classSP, cur == classZW:
+		return breakProhibited
+	case prev == classZW:
+		return breakAllowed
+	case cur == classCM, cur == classGL, prev == classGL:
+		return breakProhibited
+	case cur == classCL:
+		return breakProhibited
+	case beforeSpaces == classOP:
+		return breakProhibited
+	case prev == classSP:
+		return breakAllowed
+	case cur == classBA, cur == classHY:
+		return breakProhibited
+	case prev == classHY:
+		if unicode.IsDigit(r) {
+			return breakProhibited
+		}
+		return breakAllowed
+	case prev == classBA:
+		return breakAllowed
+	case prev == classID, cur == classID:
+		return breakAllowed
+	}
+	return breakProhibited
+}
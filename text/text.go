@@ -0,0 +1,288 @@
+// Package text measures and wraps text for Yoga nodes.
+//
+// A TextMeasurer lays out text with the metrics of a FontFace, breaking
+// lines at the opportunities of UAX #14. Text values attach the measure and
+// baseline funcs to a node and return the wrapped lines for rendering:
+//
+//	m := text.NewTextMeasurer(face)
+//	label := m.NewText("Hello, world")
+//	label.Attach(node)
+//	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+//	for _, line := range label.Lines(node.GetComputedWidth()) {
+//		draw(line.Text, line.Baseline)
+//	}
+package text
+
+import (
+	"math"
+	"unicode/utf8"
+
+	"github.com/millken/yoga"
+)
+
+// Metrics are the vertical metrics of a font face, ascent and descent are
+// positive distances from the baseline
+type Metrics struct {
+	Ascent  float32
+	Descent float32
+	LineGap float32
+}
+
+// FontFace provides the metrics of a font at a fixed size
+type FontFace interface {
+	Metrics() Metrics
+	// Advance returns the advance width of r
+	Advance(r rune) float32
+	// Kern returns the kerning adjustment between a and b, usually negative
+	Kern(a, b rune) float32
+}
+
+// FixedFace is a FontFace where every character has the same advance,
+// combining marks and zero width characters have none
+type FixedFace struct {
+	Width   float32
+	Ascent  float32
+	Descent float32
+}
+
+// Metrics implements FontFace
+func (f FixedFace) Metrics() Metrics {
+	return Metrics{Ascent: f.Ascent, Descent: f.Descent}
+}
+
+// Advance implements FontFace
+func (f FixedFace) Advance(r rune) float32 {
+	if classify(r) == classCM || r == 0x200b || r == 0x2060 || r == 0xfeff || r == 0xad {
+		return 0
+	}
+	return f.Width
+}
+
+// Kern implements FontFace
+func (f FixedFace) Kern(a, b rune) float32 {
+	return 0
+}
+
+// Line is a line of wrapped text
+type Line struct {
+	// Text is the line without trailing spaces and line breaks, with a
+	// hyphen appended when the line was broken at a soft hyphen
+	Text string
+	// Start and End are the byte offsets of the line in the source text,
+	// including trailing spaces and the line break
+	Start, End int
+	// Width is the advance width of Text
+	Width float32
+	// Y is the top of the line and Baseline the y of its baseline,
+	// relative to the top of the text
+	Y        float32
+	Baseline float32
+}
+
+// Layout is text wrapped to a width
+type Layout struct {
+	Lines []Line
+	// Width is the width of the widest line
+	Width  float32
+	Height float32
+}
+
+// TextMeasurer wraps and measures text with a font face
+type TextMeasurer struct {
+	face    FontFace
+	metrics Metrics
+	// LineHeight is the distance between baselines, by default the ascent,
+	// descent and line gap of the face. Extra space is split evenly above
+	// and below the line like CSS half-leading.
+	LineHeight float32
+}
+
+// NewTextMeasurer creates a TextMeasurer for face
+func NewTextMeasurer(face FontFace) *TextMeasurer {
+	m := face.Metrics()
+	return &TextMeasurer{face: face, metrics: m, LineHeight: m.Ascent + m.Descent + m.LineGap}
+}
+
+// baselineOffset is the distance from the top of a line to its baseline
+func (m *TextMeasurer) baselineOffset() float32 {
+	return (m.LineHeight-m.metrics.Ascent-m.metrics.Descent)/2 + m.metrics.Ascent
+}
+
+// Layout wraps s to maxWidth, text is not wrapped when maxWidth is NaN or
+// infinite. Words wider than maxWidth overflow their line.
+func (m *TextMeasurer) Layout(s string, maxWidth float32) *Layout {
+	runes := make([]rune, 0, len(s))
+	offsets := make([]int, 0, len(s)+1)
+	for i, r := range s {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(s))
+	wrap := !yoga.IsNaN(maxWidth) && !math.IsInf(float64(maxWidth), 0)
+	actions := breakActions(runes)
+
+	l := &Layout{}
+	addLine := func(start, end int) {
+		line := m.line(s, runes, offsets, start, end)
+		line.Y = float32(len(l.Lines)) * m.LineHeight
+		line.Baseline = line.Y + m.baselineOffset()
+		l.Lines = append(l.Lines, line)
+		l.Width = max(l.Width, line.Width)
+	}
+
+	// start 是当前行的起点，lastBreak 是当前行内最后一个断行机会。
+	// lineWidth 是 runes[start:i+1] 的宽度，breakWidth 是 runes[start:lastBreak]
+	// 的宽度，断行时相减得到新行的宽度，不必重新累加
+	start, lastBreak := 0, -1
+	var lineWidth, breakWidth float32
+	for i := 0; i < len(runes); i++ {
+		switch actions[i] {
+		case breakMandatory:
+			addLine(start, i)
+			start, lastBreak, lineWidth = i, -1, 0
+		case breakAllowed:
+			if i > start {
+				lastBreak, breakWidth = i, lineWidth
+			}
+		}
+		lineWidth += m.advance(runes, start, i)
+		// 行尾的空格和换行符不计入宽度
+		if !wrap || lastBreak < 0 || isSpace(runes[i]) || isLineBreak(runes[i]) {
+			continue
+		}
+		if lineWidth > maxWidth {
+			addLine(start, lastBreak)
+			// 新行的第一个字符不再与前一个字符计算字距
+			lineWidth -= breakWidth + m.advance(runes, start, lastBreak) - m.advance(runes, lastBreak, lastBreak)
+			start, lastBreak = lastBreak, -1
+		}
+	}
+	if start < len(runes) {
+		addLine(start, len(runes))
+	}
+	l.Height = float32(len(l.Lines)) * m.LineHeight
+	return l
+}
+
+func (m *TextMeasurer) line(s string, runes []rune, offsets []int, start, end int) Line {
+	// 去掉行尾的换行符和空格
+	visible := end
+	for visible > start && (isSpace(runes[visible-1]) || isLineBreak(runes[visible-1])) {
+		visible--
+	}
+	line := Line{
+		Text:  s[offsets[start]:offsets[visible]],
+		Start: offsets[start],
+		End:   offsets[end],
+		Width: m.width(runes, start, visible),
+	}
+	if visible > start && runes[visible-1] == 0xad && visible == end {
+		// 在软连字符处断行时显示连字符
+		line.Text = line.Text[:len(line.Text)-utf8.RuneLen(0xad)] + "-"
+		line.Width += m.face.Advance('-')
+	}
+	return line
+}
+
+// width returns the advance width of runes[start:end] without trailing spaces
+func (m *TextMeasurer) width(runes []rune, start, end int) float32 {
+	for end > start && (isSpace(runes[end-1]) || isLineBreak(runes[end-1])) {
+		end--
+	}
+	var w float32
+	for i := start; i < end; i++ {
+		w += m.advance(runes, start, i)
+	}
+	return w
+}
+
+// advance returns the width runes[i] adds to a line starting at start, its
+// advance and the kerning with the previous rune
+func (m *TextMeasurer) advance(runes []rune, start, i int) float32 {
+	if runes[i] == 0xad {
+		return 0
+	}
+	w := m.face.Advance(runes[i])
+	if i > start {
+		w += m.face.Kern(runes[i-1], runes[i])
+	}
+	return w
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+func isLineBreak(r rune) bool {
+	c := classify(r)
+	return c == classBK || c == classCR || c == classLF
+}
+
+// Measure measures s for a Yoga measure func: the text is wrapped to width
+// unless widthMode is MeasureModeUndefined, the measured width is width in
+// MeasureModeExactly and the widest line otherwise, and the height is the
+// height of the lines limited by height in MeasureModeAtMost
+func (m *TextMeasurer) Measure(s string, width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+	maxWidth := width
+	if widthMode == yoga.MeasureModeUndefined {
+		maxWidth = yoga.Undefined
+	}
+	l := m.Layout(s, maxWidth)
+	size := yoga.Size{Width: l.Width, Height: l.Height}
+	if widthMode == yoga.MeasureModeExactly {
+		size.Width = width
+	}
+	switch heightMode {
+	case yoga.MeasureModeExactly:
+		size.Height = height
+	case yoga.MeasureModeAtMost:
+		size.Height = min(size.Height, height)
+	}
+	return size
+}
+
+// Text is a string measured by a TextMeasurer
+type Text struct {
+	measurer *TextMeasurer
+	content  string
+}
+
+// NewText creates a Text for s
+func (m *TextMeasurer) NewText(s string) *Text {
+	return &Text{measurer: m, content: s}
+}
+
+// String returns the text
+func (t *Text) String() string {
+	return t.content
+}
+
+// Measure is the yoga.MeasureFunc of the text
+func (t *Text) Measure(width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+	return t.measurer.Measure(t.content, width, widthMode, height, heightMode)
+}
+
+// Baseline returns the baseline of the first line relative to the top of
+// the text. Yoga measures baselines from the top of the border box, the
+// baseline func set by Attach adds the padding and border of the node.
+func (t *Text) Baseline(width, height float32) float32 {
+	return t.measurer.baselineOffset()
+}
+
+// Lines returns the lines of the text wrapped to width, usually the
+// computed width of its node minus padding and border
+func (t *Text) Lines(width float32) []Line {
+	return t.measurer.Layout(t.content, width).Lines
+}
+
+// Attach sets the measure and baseline funcs of n to the text
+func (t *Text) Attach(n *yoga.Node) {
+	n.SetMeasureFunc(t.Measure)
+	n.SetNodeBaselineFunc(t.nodeBaseline)
+}
+
+// nodeBaseline offsets the baseline by the top padding and border of the
+// node it runs for
+func (t *Text) nodeBaseline(n *yoga.Node, width, height float32) float32 {
+	return n.GetComputedPadding(yoga.EdgeTop) + n.GetComputedBorder(yoga.EdgeTop) + t.Baseline(width, height)
+}
@@ -0,0 +1,152 @@
+package text
+
+import (
+	"runtime"
+	"testing"
+	"weak"
+
+	"github.com/dnsoa/go/assert"
+	"github.com/millken/yoga"
+)
+
+var face = FixedFace{Width: 10, Ascent: 8, Descent: 2}
+
+func lineTexts(l *Layout) []string {
+	var texts []string
+	for _, line := range l.Lines {
+		texts = append(texts, line.Text)
+	}
+	return texts
+}
+
+func TestBreakActions(t *testing.T) {
+	r := assert.New(t)
+	breaks := func(s string) []int {
+		var at []int
+		for i, action := range breakActions([]rune(s)) {
+			if action != breakProhibited {
+				at = append(at, i)
+			}
+		}
+		return at
+	}
+	r.Equal([]int{6}, breaks("hello world"))
+	r.Equal([]int{2}, breaks("a-b"))
+	r.Equal([]int(nil), breaks("a-1"))
+	r.Equal([]int{4}, breaks("( a b )"))
+	r.Equal([]int(nil), breaks("a b"))
+	r.Equal([]int{1, 3}, breaks("中文。字"))
+	r.Equal([]int{3}, breaks("e\u0301 x"))
+	r.Equal([]int{3, 5}, breaks("a\r\nb\nc"))
+}
+
+func TestLayout(t *testing.T) {
+	r := assert.New(t)
+	m := NewTextMeasurer(face)
+
+	l := m.Layout("hello world foo", 60)
+	r.Equal([]string{"hello", "world", "foo"}, lineTexts(l))
+	r.Equal(float32(50), l.Width)
+	r.Equal(float32(30), l.Height)
+	r.Equal(Line{Text: "world", Start: 6, End: 12, Width: 50, Y: 10, Baseline: 18}, l.Lines[1])
+
+	l = m.Layout("hello world foo", yoga.Undefined)
+	r.Equal([]string{"hello world foo"}, lineTexts(l))
+	r.Equal(float32(150), l.Width)
+
+	r.Equal([]string{"a", "", "b c"}, lineTexts(m.Layout("a\n\nb c", 100)))
+	r.Equal([]string{"ex-", "ample"}, lineTexts(m.Layout("ex\u00adample", 40)))
+	r.Equal([]string{"supercalifragilistic", "x"}, lineTexts(m.Layout("supercalifragilistic x", 50)))
+	r.Equal(0, len(m.Layout("", 10).Lines))
+
+	m.LineHeight = 20
+	l = m.Layout("a", 10)
+	r.Equal(float32(13), l.Lines[0].Baseline)
+}
+
+// kernFace kerns every pair of letters by -1
+type kernFace struct{ FixedFace }
+
+func (f kernFace) Kern(a, b rune) float32 {
+	if a != ' ' && b != ' ' {
+		return -1
+	}
+	return 0
+}
+
+func TestLayoutKerning(t *testing.T) {
+	r := assert.New(t)
+	m := NewTextMeasurer(kernFace{face})
+	// "abc" 宽 28，"abc de" 宽 28+10+19
+	l := m.Layout("abc de fgh", 57)
+	r.Equal([]string{"abc de", "fgh"}, lineTexts(l))
+	r.Equal(float32(57), l.Lines[0].Width)
+	r.Equal(float32(28), l.Lines[1].Width)
+	r.Equal([]string{"abc", "de", "fgh"}, lineTexts(m.Layout("abc de fgh", 56)))
+	// 断行后 "de fg" 宽 19+10+19
+	r.Equal([]string{"abc", "de fg"}, lineTexts(m.Layout("abc de fg", 48)))
+	r.Equal([]string{"abc", "de", "fg"}, lineTexts(m.Layout("abc de fg", 47)))
+}
+
+func TestMeasureModes(t *testing.T) {
+	r := assert.New(t)
+	m := NewTextMeasurer(face)
+	s := "hello world"
+	r.Equal(yoga.Size{Width: 110, Height: 10}, m.Measure(s, yoga.Undefined, yoga.MeasureModeUndefined, yoga.Undefined, yoga.MeasureModeUndefined))
+	r.Equal(yoga.Size{Width: 50, Height: 20}, m.Measure(s, 80, yoga.MeasureModeAtMost, yoga.Undefined, yoga.MeasureModeUndefined))
+	r.Equal(yoga.Size{Width: 80, Height: 20}, m.Measure(s, 80, yoga.MeasureModeExactly, yoga.Undefined, yoga.MeasureModeUndefined))
+	r.Equal(yoga.Size{Width: 50, Height: 15}, m.Measure(s, 80, yoga.MeasureModeAtMost, 15, yoga.MeasureModeAtMost))
+	r.Equal(yoga.Size{Width: 110, Height: 40}, m.Measure(s, 200, yoga.MeasureModeAtMost, 40, yoga.MeasureModeExactly))
+}
+
+func TestTextNode(t *testing.T) {
+	r := assert.New(t)
+	m := NewTextMeasurer(face)
+	root := yoga.NewNode()
+	root.SetFlexDirection(yoga.FlexDirectionRow)
+	root.SetAlignItems(yoga.AlignBaseline)
+	root.SetWidth(100)
+	box := yoga.NewNode()
+	box.SetWidth(20)
+	box.SetHeight(30)
+	root.InsertChild(box, 0)
+	label := yoga.NewNode()
+	label.SetFlexShrink(1)
+	root.InsertChild(label, 1)
+	padded := yoga.NewNode()
+	padded.SetPadding(yoga.EdgeTop, 4)
+	padded.SetBorder(yoga.EdgeTop, 1)
+	root.InsertChild(padded, 2)
+	defer func() {
+		padded.Destroy()
+		label.Destroy()
+		box.Destroy()
+		root.Destroy()
+	}()
+	text := m.NewText("hello world again")
+	text.Attach(label)
+	r.NotNil(label.GetBaselineFunc())
+
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(float32(50), label.GetComputedWidth())
+	r.Equal(float32(30), label.GetComputedHeight())
+	// box 没有 baseline 函数，基线是它的底边
+	r.Equal(float32(22), label.GetComputedTop())
+	// 基线从边框盒顶部算起，包括上内边距和边框
+	m.NewText("x").Attach(padded)
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(float32(17), padded.GetComputedTop())
+	r.Equal(float32(22), label.GetComputedTop())
+	r.Equal([]string{"hello", "world", "again"}, lineTexts(&Layout{Lines: text.Lines(label.GetComputedWidth())}))
+	r.Equal(2, len(text.Lines(110)))
+}
+
+func TestAttachedNodeIsCollected(t *testing.T) {
+	r := assert.New(t)
+	label := weak.Make(yoga.NewNode())
+	NewTextMeasurer(face).NewText("hello").Attach(label.Value())
+	// baseline 函数不能引用节点，否则节点的 finalizer 永远不会运行
+	runtime.GC()
+	runtime.GC()
+	r.True(label.Value() == nil)
+}
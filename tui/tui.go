@@ -0,0 +1,178 @@
+// Package tui lays out terminal user interfaces with Yoga, where one point
+// is one character cell.
+//
+//	config := tui.NewConfig()
+//	root := yoga.NewNodeWithConfig(config)
+//	...
+//	label.SetMeasureFunc(tui.Measure("\x1b[1mStatus\x1b[0m: 进行中"))
+//	root.CalculateLayout(float32(cols), float32(rows), yoga.DirectionLTR)
+//	for _, box := range tui.Boxes(root, cols, rows) {
+//		draw(box.Node, box.Visible)
+//	}
+package tui
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/millken/yoga"
+	"github.com/millken/yoga/text"
+)
+
+// NewConfig creates a config for terminal layouts: with a point scale
+// factor of 1 every computed position and size is a whole number of cells
+func NewConfig() *yoga.Config {
+	config := yoga.NewConfig()
+	config.SetPointScaleFactor(1)
+	return config
+}
+
+// CellFace is the text.FontFace of a terminal, characters advance by
+// their cell width and a line is one cell high
+type CellFace struct{}
+
+// Metrics implements text.FontFace
+func (CellFace) Metrics() text.Metrics {
+	return text.Metrics{Ascent: 1}
+}
+
+// Advance implements text.FontFace
+func (CellFace) Advance(r rune) float32 {
+	return float32(RuneWidth(r))
+}
+
+// Kern implements text.FontFace
+func (CellFace) Kern(a, b rune) float32 {
+	return 0
+}
+
+var cellMeasurer = text.NewTextMeasurer(CellFace{})
+
+// Measure returns a measure func for s in cells, wrapping s at the UAX #14
+// break opportunities when the width is constrained. ANSI escape sequences
+// in s take no space. Sizes are whole cells.
+func Measure(s string) yoga.MeasureFunc {
+	plain := StripANSI(s)
+	return func(width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+		if widthMode != yoga.MeasureModeUndefined {
+			width = float32(math.Floor(float64(width)))
+		}
+		size := cellMeasurer.Measure(plain, width, widthMode, height, heightMode)
+		return yoga.Size{
+			Width:  float32(math.Ceil(float64(size.Width))),
+			Height: float32(math.Ceil(float64(size.Height))),
+		}
+	}
+}
+
+// Lines returns the lines of s wrapped to width cells like Measure, with
+// ANSI escape sequences removed
+func Lines(s string, width int) []string {
+	var lines []string
+	for _, line := range cellMeasurer.Layout(StripANSI(s), float32(width)).Lines {
+		lines = append(lines, line.Text)
+	}
+	return lines
+}
+
+// Rect is a rectangle of cells
+type Rect struct {
+	X, Y          int
+	Width, Height int
+}
+
+// Intersect returns the intersection of r and o, which is empty when they
+// do not overlap
+func (r Rect) Intersect(o Rect) Rect {
+	x0, y0 := max(r.X, o.X), max(r.Y, o.Y)
+	x1, y1 := min(r.X+r.Width, o.X+o.Width), min(r.Y+r.Height, o.Y+o.Height)
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{X: x0, Y: y0}
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Empty reports whether r covers no cell
+func (r Rect) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Contains reports whether the cell x, y is inside r
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.Width && y < r.Y+r.Height
+}
+
+// cellRect rounds the edges of a rectangle in points to cells
+func cellRect(r yoga.Rect) Rect {
+	round := func(f float32) int {
+		return int(math.Floor(float64(f) + 0.5))
+	}
+	x0, y0 := round(r.X), round(r.Y)
+	return Rect{X: x0, Y: y0, Width: round(r.X+r.Width) - x0, Height: round(r.Y+r.Height) - y0}
+}
+
+// CellRect returns the cells covered by the border box of n, see
+// yoga.Node.AbsoluteRect
+func CellRect(n *yoga.Node) Rect {
+	return cellRect(n.AbsoluteRect())
+}
+
+// Box is a node to draw on the cell grid
+type Box struct {
+	Node *yoga.Node
+	// Path is the index path of the node such as "0/2", "" for the root
+	Path string
+	// Rect is the border box of the node in cells
+	Rect Rect
+	// Visible is the part of Rect inside the grid and the padding boxes of
+	// ancestors with overflow hidden or scroll
+	Visible Rect
+}
+
+// Boxes lists the boxes of root and its descendants in paint order, clipped
+// to a grid of cols by rows cells. Nodes with display none and boxes with
+// no visible cell are skipped; children of skipped nodes can still be
+// visible. Scroll offsets set with SetScrollOffset move the children of a
+// node.
+func Boxes(root *yoga.Node, cols, rows int) []Box {
+	var boxes []Box
+	var walk func(n *yoga.Node, path string, originX, originY float32, clip Rect)
+	walk = func(n *yoga.Node, path string, originX, originY float32, clip Rect) {
+		if n.GetDisplay() == yoga.DisplayNone {
+			return
+		}
+		r := yoga.Rect{
+			X:      originX + n.GetComputedLeft(),
+			Y:      originY + n.GetComputedTop(),
+			Width:  n.GetComputedWidth(),
+			Height: n.GetComputedHeight(),
+		}
+		box := Box{Node: n, Path: path, Rect: cellRect(r)}
+		box.Visible = box.Rect.Intersect(clip)
+		if !box.Visible.Empty() {
+			boxes = append(boxes, box)
+		}
+		if n.GetOverflow() != yoga.OverflowVisible {
+			// 子节点裁剪到 padding box
+			inner := yoga.Rect{
+				X:      r.X + n.GetComputedBorder(yoga.EdgeLeft),
+				Y:      r.Y + n.GetComputedBorder(yoga.EdgeTop),
+				Width:  r.Width - n.GetComputedBorder(yoga.EdgeLeft) - n.GetComputedBorder(yoga.EdgeRight),
+				Height: r.Height - n.GetComputedBorder(yoga.EdgeTop) - n.GetComputedBorder(yoga.EdgeBottom),
+			}
+			clip = clip.Intersect(cellRect(inner))
+		}
+		scrollX, scrollY := n.GetScrollOffset()
+		childCount := n.GetChildCount()
+		for i := uint32(0); i < childCount; i++ {
+			childPath := strconv.Itoa(int(i))
+			if path != "" {
+				childPath = path + "/" + childPath
+			}
+			walk(n.GetChild(i), childPath, r.X-scrollX, r.Y-scrollY, clip)
+		}
+	}
+	abs := root.AbsoluteRect()
+	walk(root, "", abs.X-root.GetComputedLeft(), abs.Y-root.GetComputedTop(), Rect{Width: cols, Height: rows})
+	return boxes
+}
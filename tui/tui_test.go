@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+	"github.com/millken/yoga"
+)
+
+func TestWidth(t *testing.T) {
+	r := assert.New(t)
+	r.Equal(1, RuneWidth('a'))
+	r.Equal(2, RuneWidth('中'))
+	r.Equal(2, RuneWidth('Ａ'))
+	r.Equal(2, RuneWidth(0x1f600))
+	r.Equal(0, RuneWidth(0x301))
+	r.Equal(0, RuneWidth(0x200b))
+	r.Equal(0, RuneWidth('\t'))
+	r.Equal(1, RuneWidth('é'))
+
+	r.Equal(8, StringWidth("hello 中"))
+	r.Equal(1, StringWidth("é"))
+	r.Equal(4, StringWidth("\x1b[1;31m进度\x1b[0m"))
+	r.Equal("link", StripANSI("\x1b]8;;http://example.com\x07link\x1b]8;;\x1b\\"))
+	r.Equal("bold", StripANSI("\x1b[1mbold\x1b[m"))
+}
+
+func TestSlice(t *testing.T) {
+	r := assert.New(t)
+	r.Equal("ell", Slice("hello", 1, 4))
+	r.Equal("\x1b[1mel\x1b[0m", Slice("\x1b[1mhello\x1b[0m", 1, 3))
+	r.Equal(" 文", Slice("中文字", 1, 4))
+	r.Equal("中 ", Slice("中文字", 0, 3))
+	r.Equal("éx", Slice("aéxy", 1, 3))
+	r.Equal("", Slice("abc", 5, 8))
+}
+
+func TestMeasure(t *testing.T) {
+	r := assert.New(t)
+	root := yoga.NewNodeWithConfig(NewConfig())
+	root.SetFlexDirection(yoga.FlexDirectionRow)
+	root.SetAlignItems(yoga.AlignFlexStart)
+	label := yoga.NewNodeWithConfig(NewConfig())
+	label.SetMeasureFunc(Measure("\x1b[32m状态\x1b[0m: running ok"))
+	label.SetFlexShrink(1)
+	root.InsertChild(label, 0)
+	defer func() {
+		label.Destroy()
+		root.Destroy()
+	}()
+
+	root.CalculateLayout(80, 24, yoga.DirectionLTR)
+	r.Equal(float32(16), label.GetComputedWidth())
+	r.Equal(float32(1), label.GetComputedHeight())
+
+	root.CalculateLayout(10, 24, yoga.DirectionLTR)
+	r.Equal(float32(10), label.GetComputedWidth())
+	r.Equal(float32(2), label.GetComputedHeight())
+	r.Equal([]string{"状态:", "running ok"}, Lines("\x1b[32m状态\x1b[0m: running ok", 10))
+
+	size := Measure("中文")(3.5, yoga.MeasureModeAtMost, 0, yoga.MeasureModeUndefined)
+	r.Equal(yoga.Size{Width: 2, Height: 2}, size)
+}
+
+func TestBoxes(t *testing.T) {
+	r := assert.New(t)
+	config := NewConfig()
+	root := yoga.NewNodeWithConfig(config)
+	root.SetWidth(20)
+	root.SetHeight(10)
+	root.SetPadding(yoga.EdgeAll, 1)
+	nodes := []*yoga.Node{root}
+	defer func() {
+		// 先释放子节点
+		for i := len(nodes) - 1; i >= 0; i-- {
+			nodes[i].Destroy()
+		}
+	}()
+
+	list := yoga.NewNodeWithConfig(config)
+	list.SetHeight(4)
+	list.SetBorder(yoga.EdgeAll, 1)
+	list.SetOverflow(yoga.OverflowHidden)
+	root.InsertChild(list, 0)
+	nodes = append(nodes, list)
+	for i := 0; i < 3; i++ {
+		item := yoga.NewNodeWithConfig(config)
+		item.SetHeight(1)
+		list.InsertChild(item, uint32(i))
+		nodes = append(nodes, item)
+	}
+	hidden := yoga.NewNodeWithConfig(config)
+	hidden.SetHeight(2)
+	hidden.SetDisplay(yoga.DisplayNone)
+	root.InsertChild(hidden, 1)
+	tail := yoga.NewNodeWithConfig(config)
+	tail.SetHeight(8)
+	root.InsertChild(tail, 2)
+	nodes = append(nodes, hidden, tail)
+
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(Rect{X: 1, Y: 1, Width: 18, Height: 4}, CellRect(list))
+
+	boxes := Boxes(root, 15, 8)
+	var paths []string
+	for _, box := range boxes {
+		paths = append(paths, box.Path)
+	}
+	r.Equal([]string{"", "0", "0/0", "0/1", "2"}, paths)
+	r.Equal(Rect{X: 0, Y: 0, Width: 15, Height: 8}, boxes[0].Visible)
+	r.Equal(Rect{X: 2, Y: 2, Width: 16, Height: 1}, boxes[2].Rect)
+	r.Equal(Rect{X: 2, Y: 2, Width: 13, Height: 1}, boxes[2].Visible)
+	r.Equal(Rect{X: 1, Y: 5, Width: 18, Height: 8}, boxes[4].Rect)
+	r.Equal(Rect{X: 1, Y: 5, Width: 14, Height: 3}, boxes[4].Visible)
+
+	list.SetScrollOffset(0, 1)
+	boxes = Boxes(root, 15, 8)
+	paths = paths[:0]
+	for _, box := range boxes {
+		paths = append(paths, box.Path)
+	}
+	r.Equal([]string{"", "0", "0/1", "0/2", "2"}, paths)
+	r.Equal(Rect{X: 2, Y: 2, Width: 16, Height: 1}, boxes[2].Rect)
+}
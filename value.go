@@ -4,6 +4,12 @@ package yoga
 #include "cgo_wrapper.h"
 */
 import "C"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
 
 // Value represents the Yoga style value
 type Value struct {
@@ -11,6 +17,83 @@ type Value struct {
 	Unit  Unit
 }
 
+// Pt returns a value in points
+func Pt(v float32) Value {
+	return Value{Value: v, Unit: UnitPoint}
+}
+
+// Pct returns a value in percent
+func Pct(v float32) Value {
+	return Value{Value: v, Unit: UnitPercent}
+}
+
+// Auto returns the auto value
+func Auto() Value {
+	return Value{Value: Undefined, Unit: UnitAuto}
+}
+
+// MaxContent returns the max-content value
+func MaxContent() Value {
+	return Value{Value: Undefined, Unit: UnitMaxContent}
+}
+
+// FitContent returns the fit-content value
+func FitContent() Value {
+	return Value{Value: Undefined, Unit: UnitFitContent}
+}
+
+// Stretch returns the stretch value
+func Stretch() Value {
+	return Value{Value: Undefined, Unit: UnitStretch}
+}
+
+// ParseValue parses a CSS length such as "10", "10px", "50%", "auto" or "max-content"
+func ParseValue(s string) (Value, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "undefined":
+		return Value{Value: Undefined, Unit: UnitUndefined}, nil
+	case "auto":
+		return Value{Value: Undefined, Unit: UnitAuto}, nil
+	case "max-content":
+		return Value{Value: Undefined, Unit: UnitMaxContent}, nil
+	case "fit-content":
+		return Value{Value: Undefined, Unit: UnitFitContent}, nil
+	case "stretch", "-webkit-fill-available":
+		return Value{Value: Undefined, Unit: UnitStretch}, nil
+	}
+
+	unit := UnitPoint
+	number := s
+	if strings.HasSuffix(s, "%") {
+		unit = UnitPercent
+		number = strings.TrimSuffix(s, "%")
+	} else if strings.HasSuffix(s, "px") {
+		number = strings.TrimSuffix(s, "px")
+	}
+	f, err := strconv.ParseFloat(number, 32)
+	if err != nil || math.IsInf(f, 0) {
+		return Value{}, fmt.Errorf("invalid length: %s", s)
+	}
+	if math.IsNaN(f) {
+		return Value{Value: Undefined, Unit: UnitUndefined}, nil
+	}
+	return Value{Value: float32(f), Unit: unit}, nil
+}
+
+// String formats v the way ParseValue reads it back, e.g. "10", "50%" or "auto"
+func (v Value) String() string {
+	switch v.Unit {
+	case UnitPoint:
+		return formatFloat(v.Value)
+	case UnitPercent:
+		return formatFloat(v.Value) + "%"
+	case UnitUndefined:
+		return "undefined"
+	}
+	return v.Unit.String()
+}
+
 func (v Value) IsUndefined() bool {
 	return v.Unit == UnitUndefined
 }
@@ -24,7 +107,7 @@ func (v Value) Equal(other Value) bool {
 		return false
 	}
 	switch v.Unit {
-	case UnitUndefined, UnitAuto:
+	case UnitUndefined, UnitAuto, UnitMaxContent, UnitFitContent, UnitStretch:
 		return true
 	case UnitPoint, UnitPercent:
 		return v.Value == other.Value
@@ -43,3 +126,63 @@ func valueFromYGValue(v C.YGValue) Value {
 		Unit:  Unit(v.unit),
 	}
 }
+
+// SetFlexBasisValue sets the flex basis to v
+func (n *Node) SetFlexBasisValue(v Value) error {
+	return n.flexBasisSetter().apply(v)
+}
+
+// SetWidthValue sets the width to v
+func (n *Node) SetWidthValue(v Value) error {
+	return n.widthSetter().apply(v)
+}
+
+// SetHeightValue sets the height to v
+func (n *Node) SetHeightValue(v Value) error {
+	return n.heightSetter().apply(v)
+}
+
+// SetMinWidthValue sets the minimum width to v, auto is not supported
+func (n *Node) SetMinWidthValue(v Value) error {
+	return n.minWidthSetter().apply(v)
+}
+
+// SetMinHeightValue sets the minimum height to v, auto is not supported
+func (n *Node) SetMinHeightValue(v Value) error {
+	return n.minHeightSetter().apply(v)
+}
+
+// SetMaxWidthValue sets the maximum width to v, auto is not supported
+func (n *Node) SetMaxWidthValue(v Value) error {
+	return n.maxWidthSetter().apply(v)
+}
+
+// SetMaxHeightValue sets the maximum height to v, auto is not supported
+func (n *Node) SetMaxHeightValue(v Value) error {
+	return n.maxHeightSetter().apply(v)
+}
+
+// SetMarginValue sets the margin of edge to a point, percent or auto value
+func (n *Node) SetMarginValue(edge Edge, v Value) error {
+	return marginSetter(n, edge).apply(v)
+}
+
+// SetPaddingValue sets the padding of edge to a point or percent value
+func (n *Node) SetPaddingValue(edge Edge, v Value) error {
+	return paddingSetter(n, edge).apply(v)
+}
+
+// SetBorderValue sets the border of edge to a point value
+func (n *Node) SetBorderValue(edge Edge, v Value) error {
+	return borderSetter(n, edge).apply(v)
+}
+
+// SetPositionValue sets the position of edge to a point, percent or auto value
+func (n *Node) SetPositionValue(edge Edge, v Value) error {
+	return positionSetter(n, edge).apply(v)
+}
+
+// SetGapValue sets the gap of gutter to a point or percent value
+func (n *Node) SetGapValue(gutter Gutter, v Value) error {
+	return gapSetter(n, gutter).apply(v)
+}
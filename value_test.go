@@ -0,0 +1,68 @@
+package yoga
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+)
+
+func TestValueParseAndString(t *testing.T) {
+	r := assert.New(t)
+	for _, tt := range []struct {
+		src  string
+		want Value
+		str  string
+	}{
+		{"10", Pt(10), "10"},
+		{"12.5px", Pt(12.5), "12.5"},
+		{" 50% ", Pct(50), "50%"},
+		{"auto", Auto(), "auto"},
+		{"max-content", MaxContent(), "max-content"},
+		{"fit-content", FitContent(), "fit-content"},
+		{"stretch", Stretch(), "stretch"},
+		{"undefined", Value{Value: Undefined}, "undefined"},
+	} {
+		v, err := ParseValue(tt.src)
+		r.Equal(nil, err)
+		r.True(v.Equal(tt.want))
+		r.Equal(tt.str, v.String())
+	}
+	_, err := ParseValue("wide")
+	r.NotNil(err)
+
+	r.True(MaxContent().Equal(MaxContent()))
+	r.True(Stretch().Equal(Stretch()))
+	r.False(FitContent().Equal(Stretch()))
+	r.False(Pt(1).Equal(Pct(1)))
+}
+
+func TestNodeSetValue(t *testing.T) {
+	r := assert.New(t)
+	n := NewNode()
+	defer n.Destroy()
+
+	r.Equal(nil, n.SetWidthValue(Pct(50)))
+	r.True(n.GetWidth().Equal(Pct(50)))
+	r.Equal(nil, n.SetHeightValue(FitContent()))
+	r.True(n.GetHeight().Equal(FitContent()))
+	r.Equal(nil, n.SetFlexBasisValue(Auto()))
+	r.True(n.GetFlexBasis().Equal(Auto()))
+	r.Equal(nil, n.SetMinWidthValue(Pt(10)))
+	r.True(n.GetMinWidth().Equal(Pt(10)))
+	r.Equal(nil, n.SetMaxHeightValue(Stretch()))
+	r.True(n.GetMaxHeight().Equal(Stretch()))
+	r.Equal(nil, n.SetMarginValue(EdgeLeft, Auto()))
+	r.True(n.GetMargin(EdgeLeft).Equal(Auto()))
+	r.Equal(nil, n.SetPaddingValue(EdgeTop, Pct(5)))
+	r.True(n.GetPadding(EdgeTop).Equal(Pct(5)))
+	r.Equal(nil, n.SetPositionValue(EdgeRight, Pt(3)))
+	r.True(n.GetPosition(EdgeRight).Equal(Pt(3)))
+	r.Equal(nil, n.SetGapValue(GutterRow, Pt(4)))
+	r.True(n.GetGap(GutterRow).Equal(Pt(4)))
+	r.Equal(nil, n.SetBorderValue(EdgeAll, Pt(2)))
+	r.Equal(float32(2), n.GetBorder(EdgeAll))
+
+	r.NotNil(n.SetMinWidthValue(Auto()))
+	r.NotNil(n.SetPaddingValue(EdgeAll, Auto()))
+	r.NotNil(n.SetBorderValue(EdgeAll, Pct(1)))
+}
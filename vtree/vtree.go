@@ -0,0 +1,368 @@
+// Package vtree keeps a live Yoga node tree in sync with a declarative
+// element tree.
+//
+// Each frame the UI describes the whole tree with immutable elements and
+// calls Reconcile, which applies the minimal set of InsertChild, RemoveChild
+// and SwapChild operations and style changes to the live nodes:
+//
+//	tree, err := vtree.Mount(config, vtree.E("root", "flex-direction: row",
+//		vtree.E("a", "width: 10"),
+//		vtree.E("b", "flex-grow: 1"),
+//	))
+//	...
+//	err = tree.Reconcile(vtree.E("root", "flex-direction: row",
+//		vtree.E("b", "flex-grow: 1"),
+//		vtree.E("a", "width: 20"),
+//	))
+package vtree
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/millken/yoga"
+)
+
+// Element is an immutable description of a node. Elements must not be
+// changed once passed to Mount or Reconcile, but can be shared between frames.
+type Element struct {
+	// Key identifies the element among its siblings, elements without a key
+	// are matched with previous unkeyed siblings by position
+	Key string
+	// Style is an inline style such as "width: 100; flex-grow: 1"
+	Style string
+	// Measure is the measure func of a leaf node. It is installed once and
+	// called through the current element, so the node is not marked dirty
+	// when only the func changes; change Context to remeasure the node.
+	Measure yoga.MeasureFunc
+	// Context is set as the context of the node. Nodes with a measure func
+	// are marked dirty when their context changes.
+	Context  any
+	Children []*Element
+}
+
+// E creates an element with a key, an inline style and children
+func E(key, style string, children ...*Element) *Element {
+	return &Element{Key: key, Style: style, Children: children}
+}
+
+// Tree is a live node tree mounted from an element
+type Tree struct {
+	config *yoga.Config
+	// ref 保存默认样式，scratch 用于在 CopyStyle 之前拼出新样式
+	ref     *yoga.Node
+	scratch *yoga.Node
+	root    *instance
+}
+
+// instance is the live node of an element
+type instance struct {
+	element  *Element
+	node     *yoga.Node
+	children []*instance
+}
+
+func (in *instance) measure(width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+	return in.element.Measure(width, widthMode, height, heightMode)
+}
+
+// free destroys the nodes of the subtree, children first
+func (in *instance) free() {
+	for _, child := range in.children {
+		child.free()
+	}
+	in.node.Destroy()
+}
+
+// Mount creates the live tree of e. A nil config uses the default config.
+func Mount(config *yoga.Config, e *Element) (*Tree, error) {
+	if config == nil {
+		config = yoga.NewConfig()
+	}
+	t := &Tree{
+		config:  config,
+		ref:     yoga.NewNodeWithConfig(config),
+		scratch: yoga.NewNodeWithConfig(config),
+	}
+	root, err := t.mount(e)
+	if err != nil {
+		t.Free()
+		return nil, err
+	}
+	t.root = root
+	return t, nil
+}
+
+// Root returns the root node of the live tree
+func (t *Tree) Root() *yoga.Node {
+	if t.root == nil {
+		return nil
+	}
+	return t.root.node
+}
+
+// Element returns the element the tree was last mounted or reconciled with
+func (t *Tree) Element() *Element {
+	if t.root == nil {
+		return nil
+	}
+	return t.root.element
+}
+
+// Reconcile updates the live tree from the previous element to next. The
+// root node is always reused. Other nodes are reused when their element
+// has the same key, or no key and the same position among the unkeyed
+// siblings; their measure func and context are kept. Only nodes
+// whose style, measure func or children changed are marked dirty, so the
+// next CalculateLayout only lays out what changed.
+//
+// When Reconcile fails the tree is partially updated.
+func (t *Tree) Reconcile(next *Element) error {
+	if t.root == nil {
+		return fmt.Errorf("reconcile of a freed tree")
+	}
+	return t.update(t.root, next)
+}
+
+// Free destroys the live tree
+func (t *Tree) Free() {
+	if t.root != nil {
+		t.root.free()
+		t.root = nil
+	}
+	t.ref.Destroy()
+	t.scratch.Destroy()
+}
+
+func checkElement(e *Element) error {
+	if e.Measure != nil && len(e.Children) > 0 {
+		return fmt.Errorf("element %q: nodes with a measure func cannot have children", e.Key)
+	}
+	return nil
+}
+
+func (t *Tree) mount(e *Element) (*instance, error) {
+	if err := checkElement(e); err != nil {
+		return nil, err
+	}
+	decls, err := yoga.ParseDeclarations(e.Style)
+	if err != nil {
+		return nil, fmt.Errorf("element %q: %w", e.Key, err)
+	}
+	in := &instance{element: e, node: yoga.NewNodeWithConfig(t.config)}
+	for _, decl := range decls {
+		if err := in.node.SetStyleProperty(decl.Property, decl.Value); err != nil {
+			in.free()
+			return nil, fmt.Errorf("element %q: %w", e.Key, err)
+		}
+	}
+	in.node.SetContext(e.Context)
+	for i, child := range e.Children {
+		c, err := t.mount(child)
+		if err != nil {
+			in.free()
+			return nil, err
+		}
+		in.node.InsertChild(c.node, uint32(i))
+		in.children = append(in.children, c)
+	}
+	if e.Measure != nil {
+		in.node.SetMeasureFunc(in.measure)
+	}
+	return in, nil
+}
+
+func (t *Tree) update(in *instance, next *Element) error {
+	if err := checkElement(next); err != nil {
+		return err
+	}
+	prev := in.element
+	if prev.Style != next.Style {
+		// 在 scratch 上从默认样式重建，CopyStyle 只在样式真正变化时标记 dirty
+		decls, err := yoga.ParseDeclarations(next.Style)
+		if err != nil {
+			return fmt.Errorf("element %q: %w", next.Key, err)
+		}
+		t.scratch.CopyStyle(t.ref)
+		for _, decl := range decls {
+			if err := t.scratch.SetStyleProperty(decl.Property, decl.Value); err != nil {
+				return fmt.Errorf("element %q: %w", next.Key, err)
+			}
+		}
+		in.node.CopyStyle(t.scratch)
+	}
+
+	in.element = next
+	in.node.SetContext(next.Context)
+	if prev.Measure != nil && next.Measure == nil {
+		// 只有带 measure func 的节点可以手动标记 dirty
+		in.node.MarkDirty()
+		in.node.SetMeasureFunc(nil)
+	}
+	if err := t.updateChildren(in, next.Children); err != nil {
+		return err
+	}
+	switch {
+	case prev.Measure == nil && next.Measure != nil:
+		in.node.SetMeasureFunc(in.measure)
+	case prev.Measure != nil && next.Measure != nil && !sameContext(prev.Context, next.Context):
+		in.node.MarkDirty()
+	}
+	return nil
+}
+
+// matchKeys returns the keys children are matched by, unkeyed children are
+// matched by their position among the unkeyed siblings
+func matchKeys(elements []*Element) []string {
+	keys := make([]string, len(elements))
+	unkeyed := 0
+	for i, e := range elements {
+		if e.Key != "" {
+			keys[i] = "k" + e.Key
+		} else {
+			keys[i] = fmt.Sprintf("#%d", unkeyed)
+			unkeyed++
+		}
+	}
+	return keys
+}
+
+func (t *Tree) updateChildren(in *instance, elements []*Element) error {
+	old := in.children
+	oldElements := make([]*Element, len(old))
+	for i, c := range old {
+		oldElements[i] = c.element
+	}
+	oldByKey := make(map[string]int, len(old))
+	for i, key := range matchKeys(oldElements) {
+		if _, ok := oldByKey[key]; !ok {
+			oldByKey[key] = i
+		}
+	}
+
+	target := make([]*instance, len(elements))
+	used := make([]bool, len(old))
+	for i, key := range matchKeys(elements) {
+		if j, ok := oldByKey[key]; ok && !used[j] {
+			used[j] = true
+			target[i] = old[j]
+			if err := t.update(old[j], elements[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	live := append([]*instance(nil), old...)
+	for i, e := range elements {
+		if target[i] != nil {
+			continue
+		}
+		c, err := t.mount(e)
+		if err != nil {
+			return err
+		}
+		target[i] = c
+		if i < len(old) && !used[i] {
+			// 同一位置的旧节点没有被复用，直接原地替换
+			used[i] = true
+			in.node.SwapChild(c.node, uint32(i))
+			old[i].free()
+			live[i] = c
+		}
+	}
+
+	// 删除没有被复用的旧节点
+	kept := live[:0]
+	for j, c := range live {
+		if j < len(old) && c == old[j] && !used[j] {
+			in.node.RemoveChild(c.node)
+			c.free()
+			continue
+		}
+		kept = append(kept, c)
+	}
+	live = kept
+
+	// 最长递增子序列中的节点保持不动，其余节点移动到目标位置
+	index := make(map[*instance]int, len(target))
+	for i, c := range target {
+		index[c] = i
+	}
+	order := make([]int, len(live))
+	for i, c := range live {
+		order[i] = index[c]
+	}
+	stay := longestIncreasing(order)
+	kept = live[:0]
+	for i, c := range live {
+		if stay[i] {
+			kept = append(kept, c)
+		} else {
+			in.node.RemoveChild(c.node)
+		}
+	}
+	live = kept
+	for i, c := range target {
+		if i < len(live) && live[i] == c {
+			continue
+		}
+		in.node.InsertChild(c.node, uint32(i))
+		live = append(live, nil)
+		copy(live[i+1:], live[i:])
+		live[i] = c
+	}
+	in.children = target
+	return nil
+}
+
+// longestIncreasing marks the elements of a longest strictly increasing
+// subsequence of seq
+func longestIncreasing(seq []int) []bool {
+	// tails[k] 是长度为 k+1 的递增子序列末尾元素的下标
+	var tails []int
+	prev := make([]int, len(seq))
+	for i, v := range seq {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if seq[tails[mid]] < v {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		prev[i] = -1
+		if lo > 0 {
+			prev[i] = tails[lo-1]
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
+		}
+	}
+	marks := make([]bool, len(seq))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			marks[i] = true
+		}
+	}
+	return marks
+}
+
+// sameContext compares two contexts, contexts that are not comparable,
+// including structs and arrays holding slices or maps in interface fields,
+// are always different
+func sameContext(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	// Value.Comparable 会检查接口字段里的动态值，== 才不会 panic
+	if !reflect.ValueOf(a).Comparable() || !reflect.ValueOf(b).Comparable() {
+		return false
+	}
+	return a == b
+}
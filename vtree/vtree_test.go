@@ -0,0 +1,146 @@
+package vtree
+
+import (
+	"testing"
+
+	"github.com/dnsoa/go/assert"
+	"github.com/millken/yoga"
+)
+
+func widths(n *yoga.Node) []float32 {
+	var w []float32
+	for i := uint32(0); i < n.GetChildCount(); i++ {
+		w = append(w, n.GetChild(i).GetComputedWidth())
+	}
+	return w
+}
+
+func TestReconcile(t *testing.T) {
+	r := assert.New(t)
+	tree, err := Mount(nil, E("root", "flex-direction: row; width: 100; height: 10",
+		E("a", "width: 10"),
+		E("b", "width: 20"),
+		E("c", "width: 30"),
+		E("d", "width: 40"),
+	))
+	r.Equal(nil, err)
+	defer tree.Free()
+	root := tree.Root()
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	nodes := map[string]*yoga.Node{}
+	for _, c := range tree.root.children {
+		nodes[c.element.Key] = c.node
+	}
+
+	// 移动 d 到最前面，只改变 b 的样式
+	r.Equal(nil, tree.Reconcile(E("root", "flex-direction: row; width: 100; height: 10",
+		E("d", "width: 40"),
+		E("a", "width: 10"),
+		E("b", "width: 25"),
+		E("c", "width: 30"),
+	)))
+	r.True(root.IsDirty())
+	r.False(nodes["a"].IsDirty())
+	r.True(nodes["b"].IsDirty())
+	r.False(nodes["c"].IsDirty())
+	r.False(nodes["d"].IsDirty())
+	for i, key := range []string{"d", "a", "b", "c"} {
+		r.Equal(nodes[key], tree.root.children[i].node)
+	}
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal([]float32{40, 10, 25, 30}, widths(root))
+	r.Equal(float32(50), nodes["b"].GetComputedLeft())
+
+	// 相同的元素不会产生 dirty
+	r.Equal(nil, tree.Reconcile(tree.Element()))
+	r.False(root.IsDirty())
+
+	// a 被原地替换成 e，c 被删除，b 的宽度恢复默认
+	r.Equal(nil, tree.Reconcile(E("root", "flex-direction: row; width: 100; height: 10",
+		E("d", "width: 40"),
+		E("e", "width: 5"),
+		E("b", "height: 5"),
+	)))
+	r.Equal(nodes["d"], tree.root.children[0].node)
+	r.Equal(nodes["b"], tree.root.children[2].node)
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal([]float32{40, 5, 0}, widths(root))
+	r.Equal(float32(5), nodes["b"].GetComputedHeight())
+
+	_, err = Mount(nil, E("", "colour: red"))
+	r.NotNil(err)
+	r.NotNil(tree.Reconcile(E("root", "width: wide")))
+}
+
+func TestReconcileMeasure(t *testing.T) {
+	r := assert.New(t)
+	calls := 0
+	text := func(s string) *Element {
+		return &Element{Key: "text", Context: s, Measure: func(width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+			calls++
+			return yoga.Size{Width: float32(len(s)) * 10, Height: 10}
+		}}
+	}
+	tree, err := Mount(nil, E("root", "align-items: flex-start", text("ab")))
+	r.Equal(nil, err)
+	defer tree.Free()
+	root := tree.Root()
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(float32(20), root.GetChild(0).GetComputedWidth())
+	r.Equal("ab", root.GetChild(0).GetContext())
+	node := root.GetChild(0)
+	node.SetContext(nil)
+
+	// 上下文不变时不重新测量
+	calls = 0
+	r.Equal(nil, tree.Reconcile(E("root", "align-items: flex-start", text("ab"))))
+	r.False(root.IsDirty())
+	r.Equal("ab", root.GetChild(0).GetContext())
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(0, calls)
+
+	r.Equal(nil, tree.Reconcile(E("root", "align-items: flex-start", text("abcd"))))
+	r.True(node.IsDirty())
+	r.Equal("abcd", root.GetChild(0).GetContext())
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(float32(40), root.GetChild(0).GetComputedWidth())
+
+	// 去掉 measure func 后可以挂载子节点
+	r.Equal(nil, tree.Reconcile(E("root", "align-items: flex-start", E("text", "", E("", "width: 7")))))
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(float32(7), root.GetChild(0).GetComputedWidth())
+	r.NotNil(tree.Reconcile(E("root", "", &Element{Key: "x", Measure: text("a").Measure, Children: []*Element{E("", "")}})))
+}
+
+func TestReconcileUncomparableContext(t *testing.T) {
+	r := assert.New(t)
+	type label struct{ Value any }
+	measure := func(width float32, widthMode yoga.MeasureMode, height float32, heightMode yoga.MeasureMode) yoga.Size {
+		return yoga.Size{Width: 10, Height: 10}
+	}
+	text := func(v any) *Element {
+		return &Element{Key: "text", Context: label{v}, Measure: measure}
+	}
+	tree, err := Mount(nil, E("root", "align-items: flex-start", text([]string{"a"})))
+	r.Equal(nil, err)
+	defer tree.Free()
+	root := tree.Root()
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+
+	// 接口字段里是切片的上下文不可比较，视为不同
+	r.Equal(nil, tree.Reconcile(E("root", "align-items: flex-start", text([]string{"a"}))))
+	r.True(root.GetChild(0).IsDirty())
+	r.Equal(label{[]string{"a"}}, root.GetChild(0).GetContext())
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+
+	r.Equal(nil, tree.Reconcile(E("root", "align-items: flex-start", text("a"))))
+	r.True(root.GetChild(0).IsDirty())
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	r.Equal(nil, tree.Reconcile(E("root", "align-items: flex-start", text("a"))))
+	r.False(root.IsDirty())
+
+	r.True(sameContext(label{"a"}, label{"a"}))
+	r.False(sameContext(label{[]int{1}}, label{[]int{1}}))
+	r.False(sameContext([1]any{map[int]int{}}, [1]any{map[int]int{}}))
+	r.False(sameContext(label{1}, 1))
+}
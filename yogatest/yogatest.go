@@ -0,0 +1,225 @@
+// Package yogatest provides golden-file assertions for computed Yoga layouts.
+//
+// AssertLayout serializes the computed layout of a tree into a readable text
+// form, one node per line:
+//
+//	root: left=0 top=0 width=100 height=100
+//	  0 #header: left=0 top=0 width=100 height=20
+//	  1: left=0 top=20 width=100 height=80
+//
+// and compares it against a golden file. Run the tests with -update to
+// (re)generate the golden files.
+package yogatest
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/millken/yoga"
+)
+
+var update = flag.Bool("update", false, "update yogatest golden files")
+
+// DefaultTolerance is the largest difference between an expected and an
+// actual value that AssertLayout still treats as equal
+const DefaultTolerance float32 = 0.01
+
+// Option configures AssertLayout
+type Option func(*options)
+
+type options struct {
+	tolerance float32
+}
+
+// WithTolerance sets the float tolerance used for comparisons
+func WithTolerance(tolerance float32) Option {
+	return func(o *options) {
+		o.tolerance = tolerance
+	}
+}
+
+// layoutFields lists the serialized values of every node in order
+var layoutFields = []string{"left", "top", "width", "height"}
+
+// NodeLayout is one serialized line of a layout tree
+type NodeLayout struct {
+	Depth  int
+	Label  string
+	Values []float32
+}
+
+// String formats the node the way it appears in a golden file
+func (l NodeLayout) String() string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", l.Depth))
+	b.WriteString(l.Label)
+	b.WriteString(":")
+	for i, v := range l.Values {
+		fmt.Fprintf(&b, " %s=%s", layoutFields[i], strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	return b.String()
+}
+
+// Serialize flattens the computed layout of root into golden-file lines
+func Serialize(root *yoga.Node) []NodeLayout {
+	var lines []NodeLayout
+	var walk func(n *yoga.Node, depth int, label string)
+	walk = func(n *yoga.Node, depth int, label string) {
+		if id := n.GetID(); id != "" {
+			label += " #" + id
+		}
+		lines = append(lines, NodeLayout{
+			Depth: depth,
+			Label: label,
+			Values: []float32{
+				n.GetComputedLeft(),
+				n.GetComputedTop(),
+				n.GetComputedWidth(),
+				n.GetComputedHeight(),
+			},
+		})
+		for i := uint32(0); i < n.GetChildCount(); i++ {
+			walk(n.GetChild(i), depth+1, strconv.Itoa(int(i)))
+		}
+	}
+	walk(root, 0, "root")
+	return lines
+}
+
+// Format renders serialized lines as golden-file text
+func Format(lines []NodeLayout) string {
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Parse reads golden-file text back into lines
+func Parse(text string) ([]NodeLayout, error) {
+	var lines []NodeLayout
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := scanner.Text()
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		trimmed := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(trimmed)
+		if indent%2 != 0 {
+			return nil, fmt.Errorf("line %d: odd indentation", lineNo)
+		}
+		// 标签中的 ID 可以包含冒号，而值中没有，所以在最后一个 ": " 处分开
+		i := strings.LastIndex(trimmed, ": ")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: missing ': '", lineNo)
+		}
+		label, rest := trimmed[:i], trimmed[i+2:]
+		fields := strings.Fields(rest)
+		if len(fields) != len(layoutFields) {
+			return nil, fmt.Errorf("line %d: expected %d values, got %d", lineNo, len(layoutFields), len(fields))
+		}
+		l := NodeLayout{Depth: indent / 2, Label: label, Values: make([]float32, len(fields))}
+		for i, field := range fields {
+			key, value, _ := strings.Cut(field, "=")
+			if key != layoutFields[i] {
+				return nil, fmt.Errorf("line %d: expected %s, got %s", lineNo, layoutFields[i], key)
+			}
+			f, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			l.Values[i] = float32(f)
+		}
+		lines = append(lines, l)
+	}
+	return lines, scanner.Err()
+}
+
+// AssertLayout compares the computed layout of root with the golden file at
+// path and reports a tree diff on mismatch. With -update the golden file is
+// rewritten from the current layout instead.
+func AssertLayout(t testing.TB, root *yoga.Node, path string, opts ...Option) {
+	t.Helper()
+	o := options{tolerance: DefaultTolerance}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	actual := Serialize(root)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("yogatest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(Format(actual)), 0o644); err != nil {
+			t.Fatalf("yogatest: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("yogatest: golden file %s does not exist, run with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("yogatest: %v", err)
+	}
+	expected, err := Parse(string(data))
+	if err != nil {
+		t.Fatalf("yogatest: %s: %v", path, err)
+	}
+	if diff, ok := Diff(expected, actual, o.tolerance); !ok {
+		t.Errorf("yogatest: layout does not match %s (-expected +actual):\n%s", path, diff)
+	}
+}
+
+// Diff compares two serialized trees and returns a line-based diff with
+// "-" for expected and "+" for actual lines; ok is true when they match
+func Diff(expected, actual []NodeLayout, tolerance float32) (diff string, ok bool) {
+	var b strings.Builder
+	ok = true
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		switch {
+		case i >= len(actual):
+			ok = false
+			fmt.Fprintf(&b, "- %s\n", expected[i])
+		case i >= len(expected):
+			ok = false
+			fmt.Fprintf(&b, "+ %s\n", actual[i])
+		case !lineEqual(expected[i], actual[i], tolerance):
+			ok = false
+			fmt.Fprintf(&b, "- %s\n+ %s\n", expected[i], actual[i])
+		default:
+			fmt.Fprintf(&b, "  %s\n", actual[i])
+		}
+	}
+	return b.String(), ok
+}
+
+func lineEqual(a, b NodeLayout, tolerance float32) bool {
+	if a.Depth != b.Depth || a.Label != b.Label || len(a.Values) != len(b.Values) {
+		return false
+	}
+	for i := range a.Values {
+		if !floatEqual(a.Values[i], b.Values[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+func floatEqual(a, b, tolerance float32) bool {
+	if yoga.IsNaN(a) || yoga.IsNaN(b) {
+		return yoga.IsNaN(a) && yoga.IsNaN(b)
+	}
+	return math.Abs(float64(a-b)) <= float64(tolerance)
+}
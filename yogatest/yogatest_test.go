@@ -0,0 +1,77 @@
+package yogatest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/millken/yoga"
+)
+
+func buildRow(t *testing.T) *yoga.Node {
+	root := yoga.NewNode()
+	nodes := []*yoga.Node{root}
+	t.Cleanup(func() {
+		for _, n := range nodes {
+			n.Destroy()
+		}
+	})
+	root.SetFlexDirection(yoga.FlexDirectionRow)
+	root.SetWidth(100)
+	root.SetHeight(40)
+	for i := 0; i < 3; i++ {
+		child := yoga.NewNode()
+		child.SetFlexGrow(1)
+		root.InsertChild(child, uint32(i))
+		nodes = append(nodes, child)
+	}
+	root.GetChild(1).SetID("middle")
+	root.CalculateLayout(yoga.Undefined, yoga.Undefined, yoga.DirectionLTR)
+	return root
+}
+
+func TestAssertLayout(t *testing.T) {
+	root := buildRow(t)
+	AssertLayout(t, root, "testdata/row.golden")
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	root := buildRow(t)
+	// ID 可以是任意字符串
+	root.GetChild(0).SetID("a:b")
+	root.GetChild(2).SetID("c: d:")
+
+	lines := Serialize(root)
+	parsed, err := Parse(Format(lines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := Diff(lines, parsed, 0); !ok {
+		t.Errorf("round trip mismatch:\n%s", diff)
+	}
+	if parsed[1].Label != "0 #a:b" || parsed[3].Label != "2 #c: d:" {
+		t.Errorf("labels not parsed back: %q, %q", parsed[1].Label, parsed[3].Label)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	expected, err := Parse("root: left=0 top=0 width=100 height=40\n  0: left=0 top=0 width=50 height=40\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := Parse("root: left=0 top=0 width=100 height=40\n  0: left=0 top=0 width=50.005 height=40\n  1: left=50 top=0 width=50 height=40\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, ok := Diff(expected, actual[:2], DefaultTolerance)
+	if !ok {
+		t.Errorf("expected match within tolerance:\n%s", diff)
+	}
+	diff, ok = Diff(expected, actual, DefaultTolerance)
+	if ok || !strings.Contains(diff, "+   1: left=50 top=0 width=50 height=40") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+	if _, ok = Diff(expected, actual[:2], 0); ok {
+		t.Error("expected mismatch without tolerance")
+	}
+}